@@ -0,0 +1,169 @@
+package analytics
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	AnomalyPurge      = "purge"
+	AnomalyCorrection = "correction"
+	AnomalySpike      = "spike"
+	AnomalyStall      = "stall"
+
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+const (
+	anomalyWindow     = 14
+	anomalyMinHistory = 5
+	// Seuil en écarts robustes (1,4826·MAD ≈ un écart type pour une loi normale).
+	spikeThreshold   = 6.0
+	stallMinDuration = 24 * time.Hour
+	stallMinExpected = 50.0
+)
+
+// AnomalyOptions décrit la série analysée.
+//
+// DecreaseIsPurge : une baisse est une purge (abonnés) et non une correction (vues).
+// Rounded : les valeurs publiques sont arrondies (abonnés YouTube) ; la série progresse par
+// paliers, la médiane des variations est souvent nulle et ne peut servir de référence aux pics.
+type AnomalyOptions struct {
+	DecreaseIsPurge bool
+	Rounded         bool
+}
+
+type Anomaly struct {
+	Kind     string
+	Severity string
+	Start    time.Time
+	End      time.Time
+	Observed float64
+	Expected float64
+	Score    float64
+}
+
+// DetectAnomalies analyse les variations horaires d'une série cumulative triée.
+// Chaque intervalle est comparé à la médiane et au MAD des intervalles précédents.
+func DetectAnomalies(points []Point, opts AnomalyOptions) []Anomaly {
+	var anomalies []Anomaly
+	var rates []float64
+	var stallStart *Point
+	var stallExpectedRate float64
+
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		hours := cur.Time.Sub(prev.Time).Hours()
+		if hours <= 0 {
+			continue
+		}
+		delta := cur.Value - prev.Value
+		rate := delta / hours
+
+		history := rates
+		if len(history) > anomalyWindow {
+			history = history[len(history)-anomalyWindow:]
+		}
+
+		if delta < 0 {
+			kind := AnomalyCorrection
+			if opts.DecreaseIsPurge {
+				kind = AnomalyPurge
+			}
+			relative := -delta / math.Max(prev.Value, 1)
+			anomalies = append(anomalies, Anomaly{
+				Kind:     kind,
+				Severity: severity(relative, 0.001, 0.01),
+				Start:    prev.Time,
+				End:      cur.Time,
+				Observed: cur.Value,
+				Expected: prev.Value,
+				Score:    relative,
+			})
+		} else if len(history) >= anomalyMinHistory {
+			// Sur une série arrondie, une médiane nulle traduit une progression par paliers :
+			// un palier n'est pas un pic.
+			if median := Median(history); !(opts.Rounded && median == 0) {
+				score := (rate - median) / robustScale(history, median)
+				if score > spikeThreshold && rate > 2*median {
+					anomalies = append(anomalies, Anomaly{
+						Kind:     AnomalySpike,
+						Severity: severity(score, 2*spikeThreshold, 4*spikeThreshold),
+						Start:    prev.Time,
+						End:      cur.Time,
+						Observed: delta,
+						Expected: median * hours,
+						Score:    score,
+					})
+				}
+			}
+		}
+
+		// Compteur figé : aucune variation alors que la série progressait jusque-là.
+		if delta == 0 {
+			if stallStart == nil && len(history) >= anomalyMinHistory {
+				if median := Median(history); median > 0 {
+					start := prev
+					stallStart = &start
+					stallExpectedRate = median
+				}
+			}
+		} else {
+			if stallStart != nil {
+				anomalies = appendStall(anomalies, *stallStart, prev, stallExpectedRate)
+			}
+			stallStart = nil
+		}
+
+		// Les intervalles d'un blocage en cours ne doivent pas faire baisser la référence.
+		if delta > 0 || (delta == 0 && stallStart == nil) {
+			rates = append(rates, rate)
+		}
+	}
+
+	if stallStart != nil {
+		anomalies = appendStall(anomalies, *stallStart, points[len(points)-1], stallExpectedRate)
+	}
+	return anomalies
+}
+
+func appendStall(anomalies []Anomaly, start Point, end Point, expectedRate float64) []Anomaly {
+	duration := end.Time.Sub(start.Time)
+	expected := expectedRate * duration.Hours()
+	if duration < stallMinDuration || expected < stallMinExpected {
+		return anomalies
+	}
+	score := duration.Hours() / stallMinDuration.Hours()
+	return append(anomalies, Anomaly{
+		Kind:     AnomalyStall,
+		Severity: severity(score, 3, 7),
+		Start:    start.Time,
+		End:      end.Time,
+		Observed: 0,
+		Expected: expected,
+		Score:    score,
+	})
+}
+
+func robustScale(values []float64, median float64) float64 {
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	scale := 1.4826 * Median(deviations)
+	// Série parfaitement régulière : on tolère 10 % autour de la médiane, et au moins
+	// une unité par heure pour qu'une série presque immobile ne produise pas de scores démesurés.
+	return math.Max(scale, math.Max(0.1*math.Abs(median), 1))
+}
+
+func severity(score float64, medium float64, high float64) string {
+	switch {
+	case score >= high:
+		return SeverityHigh
+	case score >= medium:
+		return SeverityMedium
+	}
+	return SeverityLow
+}
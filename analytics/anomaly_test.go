@@ -0,0 +1,132 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+var anomalyOrigin = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// hourly construit une série horaire à partir des variations successives.
+func hourly(start float64, deltas []float64) []Point {
+	points := []Point{{Time: anomalyOrigin, Value: start}}
+	for i, d := range deltas {
+		points = append(points, Point{Time: anomalyOrigin.Add(time.Duration(i+1) * time.Hour), Value: points[i].Value + d})
+	}
+	return points
+}
+
+func repeat(value float64, n int) []float64 {
+	deltas := make([]float64, n)
+	for i := range deltas {
+		deltas[i] = value
+	}
+	return deltas
+}
+
+func concat(parts ...[]float64) []float64 {
+	var all []float64
+	for _, p := range parts {
+		all = append(all, p...)
+	}
+	return all
+}
+
+// Abonnés arrondis : +10 000 tous les 4 jours, relevés quotidiens.
+func roundedSubscribers(days int) []Point {
+	points := []Point{{Time: anomalyOrigin, Value: 1230000}}
+	for d := 1; d <= days; d++ {
+		value := points[d-1].Value
+		if d%4 == 0 {
+			value += 10000
+		}
+		points = append(points, Point{Time: anomalyOrigin.Add(time.Duration(d) * 24 * time.Hour), Value: value})
+	}
+	return points
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+		opts   AnomalyOptions
+		want   []string
+	}{
+		{
+			name:   "série régulière",
+			points: hourly(1000, repeat(100, 40)),
+			want:   nil,
+		},
+		{
+			name:   "pic de vues",
+			points: hourly(1000, concat(repeat(100, 20), []float64{5000}, repeat(100, 10))),
+			want:   []string{AnomalySpike},
+		},
+		{
+			name:   "correction de vues",
+			points: hourly(1000, concat(repeat(100, 20), []float64{-300}, repeat(100, 10))),
+			want:   []string{AnomalyCorrection},
+		},
+		{
+			name:   "purge d'abonnés",
+			points: hourly(50000, concat(repeat(10, 20), []float64{-2000}, repeat(10, 10))),
+			opts:   AnomalyOptions{DecreaseIsPurge: true, Rounded: true},
+			want:   []string{AnomalyPurge},
+		},
+		{
+			name:   "compteur figé",
+			points: hourly(1000, concat(repeat(100, 20), repeat(0, 30), repeat(100, 5))),
+			want:   []string{AnomalyStall},
+		},
+		{
+			name:   "compteur figé jusqu'au dernier relevé",
+			points: hourly(1000, concat(repeat(100, 20), repeat(0, 30))),
+			want:   []string{AnomalyStall},
+		},
+		{
+			name:   "blocage trop court",
+			points: hourly(1000, concat(repeat(100, 20), repeat(0, 10), repeat(100, 5))),
+			want:   nil,
+		},
+		{
+			name:   "paliers d'arrondi des abonnés",
+			points: roundedSubscribers(30),
+			opts:   AnomalyOptions{DecreaseIsPurge: true, Rounded: true},
+			want:   nil,
+		},
+		{
+			name:   "historique insuffisant",
+			points: hourly(1000, []float64{100, 100, 5000}),
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, a := range DetectAnomalies(tt.points, tt.opts) {
+				got = append(got, a.Kind)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("DetectAnomalies() = %v, attendu %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("DetectAnomalies() = %v, attendu %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectAnomaliesSeverity(t *testing.T) {
+	anomalies := DetectAnomalies(hourly(100000, concat(repeat(100, 20), []float64{-5000})), AnomalyOptions{})
+	if len(anomalies) != 1 || anomalies[0].Severity != SeverityHigh {
+		t.Fatalf("correction de 5 %% : %+v, attendu une sévérité high", anomalies)
+	}
+
+	stall := DetectAnomalies(hourly(1000, concat(repeat(100, 20), repeat(0, 24*8))), AnomalyOptions{})
+	if len(stall) != 1 || stall[0].Severity != SeverityHigh {
+		t.Fatalf("blocage de 8 jours : %+v, attendu une sévérité high", stall)
+	}
+}
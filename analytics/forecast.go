@@ -0,0 +1,211 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	ModelLinear     = "linear"
+	ModelLog        = "log"
+	ModelSaturating = "saturating"
+)
+
+// Bornes (par jour) explorées pour le taux r du modèle saturant.
+const (
+	saturatingMinRate = 1e-4
+	saturatingMaxRate = 2.0
+	saturatingSteps   = 80
+)
+
+// Fit est un modèle ajusté sur une série ; le temps est exprimé en jours depuis Origin.
+//
+//	linear     : y = A + B·t
+//	log        : y = A + B·ln(t+1)
+//	saturating : y = A + B·e^(-R·t)
+type Fit struct {
+	Model  string
+	Origin time.Time
+	A      float64
+	B      float64
+	R      float64
+	R2     float64
+	AdjR2  float64
+	Sigma  float64
+}
+
+func (f Fit) Predict(t time.Time) float64 {
+	x := days(f.Origin, t)
+	switch f.Model {
+	case ModelLog:
+		return f.A + f.B*math.Log(math.Max(x, 0)+1)
+	case ModelSaturating:
+		return f.A + f.B*math.Exp(-f.R*x)
+	}
+	return f.A + f.B*x
+}
+
+func days(origin time.Time, t time.Time) float64 {
+	return t.Sub(origin).Hours() / 24
+}
+
+// FitModels ajuste les trois modèles et les renvoie du meilleur R² ajusté au moins bon :
+// le R² brut favoriserait toujours le modèle saturant et son paramètre supplémentaire.
+// Un modèle n'est proposé que s'il reste au moins un degré de liberté.
+func FitModels(points []Point) []Fit {
+	if len(points) < 3 {
+		return nil
+	}
+	origin := points[0].Time
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = days(origin, p.Time)
+		ys[i] = p.Value
+	}
+
+	var fits []Fit
+	if fit, ok := fitTransformed(ModelLinear, xs, ys, func(x float64) float64 { return x }, 2); ok {
+		fits = append(fits, fit)
+	}
+	if fit, ok := fitTransformed(ModelLog, xs, ys, func(x float64) float64 { return math.Log(x + 1) }, 2); ok {
+		fits = append(fits, fit)
+	}
+	if len(points) > 3 {
+		if fit, ok := fitSaturating(xs, ys); ok {
+			fits = append(fits, fit)
+		}
+	}
+
+	for i := range fits {
+		fits[i].Origin = origin
+	}
+	sort.SliceStable(fits, func(i, j int) bool { return fits[i].AdjR2 > fits[j].AdjR2 })
+	return fits
+}
+
+func fitTransformed(model string, xs []float64, ys []float64, transform func(float64) float64, params int) (Fit, bool) {
+	tx := make([]float64, len(xs))
+	for i, x := range xs {
+		tx[i] = transform(x)
+	}
+	a, b, ok := leastSquares(tx, ys)
+	if !ok {
+		return Fit{}, false
+	}
+	r2, adjR2, sigma := goodness(ys, func(i int) float64 { return a + b*tx[i] }, params)
+	return Fit{Model: model, A: a, B: b, R2: r2, AdjR2: adjR2, Sigma: sigma}, true
+}
+
+// Pour r fixé le modèle saturant est linéaire en e^(-r·t) : on cherche r sur une grille
+// logarithmique et on garde l'ajustement de plus petite erreur.
+func fitSaturating(xs []float64, ys []float64) (Fit, bool) {
+	best := Fit{Model: ModelSaturating}
+	found := false
+	ratio := math.Pow(saturatingMaxRate/saturatingMinRate, 1/float64(saturatingSteps-1))
+	for i, r := 0, saturatingMinRate; i < saturatingSteps; i, r = i+1, r*ratio {
+		rate := r
+		fit, ok := fitTransformed(ModelSaturating, xs, ys, func(x float64) float64 { return math.Exp(-rate * x) }, 3)
+		if !ok {
+			continue
+		}
+		if !found || fit.R2 > best.R2 {
+			fit.R = rate
+			best = fit
+			found = true
+		}
+	}
+	return best, found
+}
+
+func leastSquares(xs []float64, ys []float64) (float64, float64, bool) {
+	n := float64(len(xs))
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	denom := n*sxx - sx*sx
+	if n < 2 || math.Abs(denom) < 1e-12 {
+		return 0, 0, false
+	}
+	b := (n*sxy - sx*sy) / denom
+	return (sy - b*sx) / n, b, true
+}
+
+// goodness renvoie le R², le R² ajusté et l'écart type des résidus, ces deux derniers
+// corrigés du nombre de paramètres.
+func goodness(ys []float64, predict func(int) float64, params int) (float64, float64, float64) {
+	var mean float64
+	for _, y := range ys {
+		mean += y
+	}
+	mean /= float64(len(ys))
+
+	var ssRes, ssTot float64
+	for i, y := range ys {
+		residual := y - predict(i)
+		ssRes += residual * residual
+		ssTot += (y - mean) * (y - mean)
+	}
+
+	r2 := 1.0
+	if ssTot > 0 {
+		r2 = 1 - ssRes/ssTot
+	} else if ssRes > 0 {
+		r2 = 0
+	}
+	adjR2, sigma := r2, 0.0
+	if dof := len(ys) - params; dof > 0 {
+		adjR2 = 1 - (1-r2)*float64(len(ys)-1)/float64(dof)
+		sigma = math.Sqrt(ssRes / float64(dof))
+	}
+	return r2, adjR2, sigma
+}
+
+// Band renvoie la projection à l'instant t avec un intervalle à 95 % (±1,96σ).
+func (f Fit) Band(t time.Time) (value float64, lower float64, upper float64) {
+	value = f.Predict(t)
+	return value, value - 1.96*f.Sigma, value + 1.96*f.Sigma
+}
+
+// Crossing cherche, pas à pas après from et jusqu'à until, le premier instant où la
+// projection, sa borne haute et sa borne basse atteignent target. Une date nulle
+// signifie que le seuil n'est pas atteint dans l'horizon.
+func (f Fit) Crossing(target float64, from time.Time, until time.Time, step time.Duration) (eta *time.Time, earliest *time.Time, latest *time.Time) {
+	for t := from; !t.After(until); t = t.Add(step) {
+		value, lower, upper := f.Band(t)
+		if earliest == nil && upper >= target {
+			at := t
+			earliest = &at
+		}
+		if eta == nil && value >= target {
+			at := t
+			eta = &at
+		}
+		if latest == nil && lower >= target {
+			at := t
+			latest = &at
+			break
+		}
+	}
+	return eta, earliest, latest
+}
+
+// Slope renvoie la pente (par jour) de la droite des moindres carrés.
+func Slope(points []Point) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = days(points[0].Time, p.Time)
+		ys[i] = p.Value
+	}
+	_, b, ok := leastSquares(xs, ys)
+	return b, ok
+}
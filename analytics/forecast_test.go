@@ -0,0 +1,172 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var forecastOrigin = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// daily construit une série quotidienne de f(t), perturbée d'un bruit alterné ±noise.
+func daily(n int, noise float64, f func(t float64) float64) []Point {
+	points := make([]Point, n)
+	for i := range points {
+		sign := 1.0
+		if i%2 == 1 {
+			sign = -1
+		}
+		points[i] = Point{Time: forecastOrigin.AddDate(0, 0, i), Value: f(float64(i)) + sign*noise}
+	}
+	return points
+}
+
+func linearSeries() []Point {
+	return daily(30, 5, func(t float64) float64 { return 1000 + 50*t })
+}
+
+func logSeries() []Point {
+	return daily(30, 5, func(t float64) float64 { return 1000 + 2000*math.Log(t+1) })
+}
+
+func saturatingSeries() []Point {
+	return daily(30, 5, func(t float64) float64 { return 10000 - 8000*math.Exp(-0.2*t) })
+}
+
+func TestFitModelsSelection(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+		model  string
+	}{
+		{"linéaire", linearSeries(), ModelLinear},
+		{"logarithmique", logSeries(), ModelLog},
+		{"saturant", saturatingSeries(), ModelSaturating},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fits := FitModels(tt.points)
+			if len(fits) != 3 {
+				t.Fatalf("FitModels() = %d modèles, attendu 3", len(fits))
+			}
+			if fits[0].Model != tt.model {
+				t.Errorf("meilleur modèle = %s, attendu %s (%+v)", fits[0].Model, tt.model, fits)
+			}
+			for i := 1; i < len(fits); i++ {
+				if fits[i].AdjR2 > fits[i-1].AdjR2 {
+					t.Errorf("modèles non triés par R² ajusté : %+v", fits)
+				}
+			}
+			if !fits[0].Origin.Equal(forecastOrigin) {
+				t.Errorf("origine = %v, attendu %v", fits[0].Origin, forecastOrigin)
+			}
+			if fits[0].AdjR2 < 0.99 {
+				t.Errorf("R² ajusté = %v, attendu au moins 0,99", fits[0].AdjR2)
+			}
+		})
+	}
+}
+
+func TestFitModelsTooFewPoints(t *testing.T) {
+	if fits := FitModels(linearSeries()[:2]); fits != nil {
+		t.Errorf("FitModels() sur 2 points = %+v, attendu nil", fits)
+	}
+	fits := FitModels(linearSeries()[:3])
+	for _, fit := range fits {
+		if fit.Model == ModelSaturating {
+			t.Errorf("modèle saturant proposé sans degré de liberté : %+v", fit)
+		}
+	}
+}
+
+func TestFitLinearCoefficients(t *testing.T) {
+	fit := FitModels(linearSeries())[0]
+	if math.Abs(fit.A-1000) > 5 || math.Abs(fit.B-50) > 0.5 {
+		t.Errorf("ajustement linéaire A = %v, B = %v ; attendu environ 1000 et 50", fit.A, fit.B)
+	}
+	// Bruit ±5 : l'écart type des résidus en est proche.
+	if fit.Sigma < 4.5 || fit.Sigma > 5.5 {
+		t.Errorf("Sigma = %v, attendu environ 5", fit.Sigma)
+	}
+}
+
+func TestBand(t *testing.T) {
+	for _, points := range [][]Point{linearSeries(), logSeries(), saturatingSeries()} {
+		fit := FitModels(points)[0]
+		inside := 0
+		for _, p := range points {
+			value, lower, upper := fit.Band(p.Time)
+			if !almostEqual(value, fit.Predict(p.Time)) {
+				t.Errorf("%s : Band() = %v, Predict() = %v", fit.Model, value, fit.Predict(p.Time))
+			}
+			if !almostEqual(value-lower, 1.96*fit.Sigma) || !almostEqual(upper-value, 1.96*fit.Sigma) {
+				t.Errorf("%s : bande [%v, %v] autour de %v, attendu ±1,96σ (σ = %v)", fit.Model, lower, upper, value, fit.Sigma)
+			}
+			if p.Value >= lower && p.Value <= upper {
+				inside++
+			}
+		}
+		// Intervalle à 95 % : quelques relevés peuvent en sortir, pas davantage.
+		if coverage := float64(inside) / float64(len(points)); coverage < 0.9 {
+			t.Errorf("%s : %.0f %% des relevés dans la bande, attendu au moins 90 %%", fit.Model, 100*coverage)
+		}
+	}
+}
+
+func TestCrossing(t *testing.T) {
+	linear := Fit{Model: ModelLinear, Origin: forecastOrigin, A: 1000, B: 100, Sigma: 50}
+	saturating := Fit{Model: ModelSaturating, Origin: forecastOrigin, A: 10000, B: -8000, R: 0.2, Sigma: 10}
+	declining := Fit{Model: ModelLinear, Origin: forecastOrigin, A: 1000, B: -10, Sigma: 5}
+	day := func(d float64) time.Time { return forecastOrigin.Add(time.Duration(d * 24 * float64(time.Hour))) }
+
+	tests := []struct {
+		name                  string
+		fit                   Fit
+		target                float64
+		until                 time.Time
+		eta, earliest, latest *time.Time
+	}{
+		{
+			// 1,96σ = 98 vues, soit un peu moins d'un jour de progression.
+			name:     "seuil atteint",
+			fit:      linear,
+			target:   2000,
+			until:    day(30),
+			eta:      timePtr(day(10)),
+			earliest: timePtr(day(10)),
+			latest:   timePtr(day(11)),
+		},
+		{
+			name:     "borne basse hors de l'horizon",
+			fit:      linear,
+			target:   2000,
+			until:    day(10),
+			eta:      timePtr(day(10)),
+			earliest: timePtr(day(10)),
+		},
+		{"asymptote sous le seuil", saturating, 20000, day(365), nil, nil, nil},
+		{"série en baisse", declining, 2000, day(365), nil, nil, nil},
+		{"horizon trop court", linear, 5000, day(20), nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eta, earliest, latest := tt.fit.Crossing(tt.target, forecastOrigin, tt.until, 24*time.Hour)
+			checkTime(t, "eta", eta, tt.eta)
+			checkTime(t, "earliest", earliest, tt.earliest)
+			checkTime(t, "latest", latest, tt.latest)
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func checkTime(t *testing.T, name string, got *time.Time, want *time.Time) {
+	t.Helper()
+	if (got == nil) != (want == nil) || (got != nil && !got.Equal(*want)) {
+		t.Errorf("%s = %v, attendu %v", name, got, want)
+	}
+}
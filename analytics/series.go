@@ -0,0 +1,148 @@
+// Package analytics regroupe les calculs sur les séries de statistiques (channel_stats,
+// video_stats) : interpolation, rééchantillonnage, écarts, ajustements. Il ne dépend
+// ni de la base ni de l'API YouTube.
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point est un relevé cumulatif (vues, abonnés...) à un instant donné.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+type Interval struct {
+	Start        time.Time
+	End          time.Time
+	StartValue   float64
+	EndValue     float64
+	Delta        float64
+	PerHour      float64
+	Acceleration *float64
+}
+
+// SortPoints trie par date et ne garde que le dernier relevé d'un même instant.
+func SortPoints(points []Point) []Point {
+	sorted := append([]Point(nil), points...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	out := sorted[:0]
+	for _, p := range sorted {
+		if len(out) > 0 && out[len(out)-1].Time.Equal(p.Time) {
+			out[len(out)-1] = p
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// ValueAt interpole linéairement la série (triée) à l'instant t.
+// Hors de la plage observée, ok vaut false : on n'extrapole pas.
+func ValueAt(points []Point, t time.Time) (float64, bool) {
+	n := len(points)
+	if n == 0 || t.Before(points[0].Time) || t.After(points[n-1].Time) {
+		return 0, false
+	}
+
+	i := sort.Search(n, func(i int) bool { return !points[i].Time.Before(t) })
+	if points[i].Time.Equal(t) {
+		return points[i].Value, true
+	}
+
+	prev, next := points[i-1], points[i]
+	ratio := float64(t.Sub(prev.Time)) / float64(next.Time.Sub(prev.Time))
+	return prev.Value + ratio*(next.Value-prev.Value), true
+}
+
+// Resample ramène une série à espacement irrégulier sur une grille régulière [from, to]
+// de pas step, alignée sur les multiples du pas.
+func Resample(points []Point, from time.Time, to time.Time, step time.Duration) []Point {
+	if step <= 0 || len(points) == 0 {
+		return nil
+	}
+
+	var grid []Point
+	for t := from.Truncate(step); !t.After(to); t = t.Add(step) {
+		if t.Before(from) {
+			continue
+		}
+		if v, ok := ValueAt(points, t); ok {
+			grid = append(grid, Point{Time: t, Value: v})
+		}
+	}
+	return grid
+}
+
+// Intervals calcule, entre points consécutifs, l'écart, le rythme horaire et
+// l'accélération (variation du rythme horaire par heure).
+func Intervals(points []Point) []Interval {
+	var intervals []Interval
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		hours := cur.Time.Sub(prev.Time).Hours()
+		if hours <= 0 {
+			continue
+		}
+
+		interval := Interval{
+			Start:      prev.Time,
+			End:        cur.Time,
+			StartValue: prev.Value,
+			EndValue:   cur.Value,
+			Delta:      cur.Value - prev.Value,
+			PerHour:    (cur.Value - prev.Value) / hours,
+		}
+		if n := len(intervals); n > 0 {
+			last := intervals[n-1]
+			midGap := interval.Start.Add(interval.End.Sub(interval.Start) / 2).Sub(last.Start.Add(last.End.Sub(last.Start) / 2)).Hours()
+			if midGap > 0 {
+				acceleration := (interval.PerHour - last.PerHour) / midGap
+				interval.Acceleration = &acceleration
+			}
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals
+}
+
+// Gain renvoie la progression sur la fenêtre se terminant au dernier relevé.
+func Gain(points []Point, window time.Duration) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	last := points[len(points)-1]
+	start, ok := ValueAt(points, last.Time.Add(-window))
+	if !ok {
+		return 0, false
+	}
+	return last.Value - start, true
+}
+
+// ParseWindow accepte les durées Go ("6h", "90m") ainsi que les jours et semaines ("7d", "2w").
+func ParseWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, found := strings.CutSuffix(s, suffix); found {
+			n, err := strconv.ParseFloat(number, 64)
+			// !(n > 0) écarte aussi NaN ; une valeur infime ("1e-20d") s'arrondit à une durée nulle.
+			if err != nil || !(n > 0) || n > float64(math.MaxInt64)/float64(unit) || time.Duration(n*float64(unit)) <= 0 {
+				return 0, fmt.Errorf("durée invalide : %q", s)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("durée invalide : %q", s)
+	}
+	return d, nil
+}
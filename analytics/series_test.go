@@ -0,0 +1,242 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var seriesOrigin = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(minutes float64) time.Time {
+	return seriesOrigin.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSortPoints(t *testing.T) {
+	points := SortPoints([]Point{
+		{Time: at(60), Value: 3},
+		{Time: at(0), Value: 1},
+		{Time: at(60), Value: 4},
+		{Time: at(30), Value: 2},
+	})
+
+	want := []Point{{Time: at(0), Value: 1}, {Time: at(30), Value: 2}, {Time: at(60), Value: 4}}
+	if len(points) != len(want) {
+		t.Fatalf("SortPoints() = %+v, attendu %+v", points, want)
+	}
+	for i := range want {
+		if !points[i].Time.Equal(want[i].Time) || points[i].Value != want[i].Value {
+			t.Errorf("point %d = %+v, attendu %+v", i, points[i], want[i])
+		}
+	}
+}
+
+func TestValueAt(t *testing.T) {
+	// Écarts irréguliers : 10 min, puis 2h.
+	points := []Point{{Time: at(0), Value: 100}, {Time: at(10), Value: 120}, {Time: at(130), Value: 360}}
+
+	tests := []struct {
+		name   string
+		points []Point
+		t      time.Time
+		want   float64
+		ok     bool
+	}{
+		{"série vide", nil, at(0), 0, false},
+		{"avant le premier relevé", points, at(-1), 0, false},
+		{"après le dernier relevé", points, at(131), 0, false},
+		{"premier relevé", points, at(0), 100, true},
+		{"dernier relevé", points, at(130), 360, true},
+		{"relevé exact", points, at(10), 120, true},
+		{"intervalle court", points, at(5), 110, true},
+		{"intervalle long", points, at(70), 240, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ValueAt(tt.points, tt.t)
+			if ok != tt.ok || !almostEqual(got, tt.want) {
+				t.Errorf("ValueAt() = %v, %v ; attendu %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestResample(t *testing.T) {
+	// Une vue par minute depuis 0h10, relevée à intervalles irréguliers.
+	points := []Point{{Time: at(10), Value: 0}, {Time: at(100), Value: 90}, {Time: at(310), Value: 300}}
+
+	tests := []struct {
+		name   string
+		from   time.Time
+		to     time.Time
+		step   time.Duration
+		points []Point
+		want   map[time.Time]float64
+	}{
+		{
+			name:   "grille horaire alignée",
+			from:   at(10),
+			to:     at(360),
+			step:   time.Hour,
+			points: points,
+			want:   map[time.Time]float64{at(60): 50, at(120): 110, at(180): 170, at(240): 230, at(300): 290},
+		},
+		{
+			name:   "bornes hors de la série",
+			from:   at(-600),
+			to:     at(600),
+			step:   2 * time.Hour,
+			points: points,
+			want:   map[time.Time]float64{at(120): 110, at(240): 230},
+		},
+		{
+			name:   "fenêtre sans relevé",
+			from:   at(400),
+			to:     at(600),
+			step:   time.Hour,
+			points: points,
+			want:   map[time.Time]float64{},
+		},
+		{"pas nul", at(0), at(600), 0, points, nil},
+		{"série vide", at(0), at(600), time.Hour, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Resample(tt.points, tt.from, tt.to, tt.step)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Resample() = %+v, attendu %v points", got, len(tt.want))
+			}
+			for i, p := range got {
+				if i > 0 && p.Time.Sub(got[i-1].Time) != tt.step {
+					t.Errorf("pas irrégulier entre %v et %v", got[i-1].Time, p.Time)
+				}
+				want, ok := tt.want[p.Time]
+				if !ok || !almostEqual(p.Value, want) {
+					t.Errorf("point %v = %v, attendu %v (présent : %v)", p.Time, p.Value, want, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestIntervals(t *testing.T) {
+	tests := []struct {
+		name         string
+		points       []Point
+		perHour      []float64
+		deltas       []float64
+		acceleration []*float64
+	}{
+		{"série vide", nil, nil, nil, nil},
+		{"un seul relevé", []Point{{Time: at(0), Value: 10}}, nil, nil, nil},
+		{
+			name:         "écarts irréguliers",
+			points:       []Point{{Time: at(0), Value: 0}, {Time: at(120), Value: 20}, {Time: at(240), Value: 60}},
+			perHour:      []float64{10, 20},
+			deltas:       []float64{20, 40},
+			acceleration: []*float64{nil, ptr(5)},
+		},
+		{
+			name:         "relevés simultanés ignorés",
+			points:       []Point{{Time: at(0), Value: 0}, {Time: at(120), Value: 20}, {Time: at(120), Value: 22}, {Time: at(240), Value: 60}},
+			perHour:      []float64{10, 19},
+			deltas:       []float64{20, 38},
+			acceleration: []*float64{nil, ptr(4.5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Intervals(tt.points)
+			if len(got) != len(tt.perHour) {
+				t.Fatalf("Intervals() = %+v, attendu %d intervalles", got, len(tt.perHour))
+			}
+			for i, interval := range got {
+				if !almostEqual(interval.PerHour, tt.perHour[i]) || !almostEqual(interval.Delta, tt.deltas[i]) {
+					t.Errorf("intervalle %d : delta %v, rythme %v ; attendu %v, %v", i, interval.Delta, interval.PerHour, tt.deltas[i], tt.perHour[i])
+				}
+				want := tt.acceleration[i]
+				if (interval.Acceleration == nil) != (want == nil) || (want != nil && !almostEqual(*interval.Acceleration, *want)) {
+					t.Errorf("intervalle %d : accélération %v, attendu %v", i, interval.Acceleration, want)
+				}
+			}
+		})
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func TestGain(t *testing.T) {
+	points := []Point{{Time: at(0), Value: 0}, {Time: at(600), Value: 100}, {Time: at(720), Value: 160}}
+
+	tests := []struct {
+		name   string
+		points []Point
+		window time.Duration
+		want   float64
+		ok     bool
+	}{
+		{"série vide", nil, time.Hour, 0, false},
+		{"fenêtre sur le dernier intervalle", points, 2 * time.Hour, 60, true},
+		{"fenêtre interpolée", points, 6 * time.Hour, 100, true},
+		{"fenêtre égale à la série", points, 12 * time.Hour, 160, true},
+		{"fenêtre plus longue que la série", points, 13 * time.Hour, 0, false},
+		{"fenêtre nulle", points, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Gain(tt.points, tt.window)
+			if ok != tt.ok || !almostEqual(got, tt.want) {
+				t.Errorf("Gain() = %v, %v ; attendu %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"6h", 6 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{" 3d ", 3 * 24 * time.Hour, false},
+		{"", 0, true},
+		{"d", 0, true},
+		{"abc", 0, true},
+		{"7j", 0, true},
+		{"0d", 0, true},
+		{"-1w", 0, true},
+		{"0s", 0, true},
+		{"-6h", 0, true},
+		{"1e-20d", 0, true},
+		{"NaNd", 0, true},
+		{"Infw", 0, true},
+		{"1e300d", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseWindow(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWindow(%q) erreur = %v, attendu une erreur : %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseWindow(%q) = %v, attendu %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
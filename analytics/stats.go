@@ -0,0 +1,27 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+// Percentile renvoie le p-ième centile (0 ≤ p ≤ 1) par interpolation linéaire.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+func Median(values []float64) float64 {
+	return Percentile(values, 0.5)
+}
@@ -0,0 +1,41 @@
+// Hub PubSubHubbub local pour le développement.
+//
+//	go run ./cmd/localhub -addr :4100 -public-url http://localhost:4100
+//
+// Lancer ensuite le backend avec :
+//
+//	HUB_URL=http://localhost:4100/subscribe
+//	PUBLIC_BASE_URL=http://localhost:4000
+//	YOUTUBE_API_URL=http://localhost:4100/youtube/v3
+//	YOUTUBE_FEED_URL=http://localhost:4100/feeds/videos.xml
+//	SHORTS_PROBE_URL=http://localhost:4100
+//
+// puis injecter des vidéos ("short": true pour un Short, détecté par la sonde /shorts/{id}) :
+//
+//	curl -X POST localhost:4100/inject/upload -d '{"channel_id":"UC...","video_id":"abc123","title":"Test"}'
+//	curl -X POST localhost:4100/inject/upload -d '{"channel_id":"UC...","video_id":"def456","short":true,"duration":"PT45S"}'
+//	curl -X POST localhost:4100/inject/delete -d '{"channel_id":"UC...","video_id":"abc123"}'
+//
+// Le même parcours est rejoué automatiquement contre une base de test dédiée :
+//
+//	LOCALHUB_E2E_DB=ytst_e2e DB_USER=... DB_PASS=... DB_HOST=localhost DB_PORT=5432 go test ./localhub/
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"ytst-back/localhub"
+)
+
+func main() {
+	addr := flag.String("addr", ":4100", "adresse d'écoute du hub")
+	publicURL := flag.String("public-url", "http://localhost:4100", "URL publique du hub (en-têtes Link rel=hub)")
+	flag.Parse()
+
+	hub := localhub.New(*publicURL)
+
+	log.Printf("Hub local à l'écoute sur %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, hub.Handler()))
+}
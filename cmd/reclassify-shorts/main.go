@@ -0,0 +1,41 @@
+// Reclassifie les vidéos existantes en Shorts / format long.
+//
+//	go run ./cmd/reclassify-shorts        # vidéos non confirmées par la sonde
+//	go run ./cmd/reclassify-shorts -all   # toutes les vidéos
+package main
+
+import (
+	"flag"
+	"log"
+
+	"ytst-back/config"
+	"ytst-back/db"
+	"ytst-back/logic"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	all := flag.Bool("all", false, "reclassifier toutes les vidéos, y compris celles déjà confirmées par la sonde")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Erreur lors du chargement de la configuration : %v", err)
+	}
+
+	dbConn, err := db.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Erreur lors de la connexion à la base de données : %v", err)
+	}
+	defer dbConn.Close()
+
+	if err := db.RunMigrations(dbConn); err != nil {
+		log.Fatalf("Erreur lors de la création des tables : %v", err)
+	}
+
+	logic.Configure(cfg)
+	if err := logic.ReclassifyShorts(dbConn, *all); err != nil {
+		log.Fatalf("Erreur lors de la reclassification : %v", err)
+	}
+}
@@ -3,15 +3,32 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-var WebsiteAccess string = "https://ytst.flgr.fr"
+const CallbackPath = "/youtube/callback"
+
+func getEnv(key string, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
 
 func Load() (*Config, error) {
 	// Charger le fichier .env, si présent
 	godotenv.Load()
+	// Fichier de configuration propre à l'environnement (staging, ...), prioritaire sur le .env
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := godotenv.Overload(path); err != nil {
+			return nil, fmt.Errorf("cannot load CONFIG_FILE %q: %v", path, err)
+		}
+	}
 
 	cfg := &Config{
 		DBUser: os.Getenv("DB_USER"),
@@ -22,9 +39,74 @@ func Load() (*Config, error) {
 		DBSSL:  "disable",
 
 		YouTubeAPIKey: os.Getenv("GOOGLE_API_KEY"),
-		WebsiteAccess: WebsiteAccess,
+		// YOUTUBE_API_URL et YOUTUBE_FEED_URL permettent de viser un bouchon local (cmd/localhub) en développement.
+		YouTubeAPIURL:  strings.TrimRight(getEnv("YOUTUBE_API_URL", "https://www.googleapis.com/youtube/v3"), "/"),
+		YouTubeFeedURL: getEnv("YOUTUBE_FEED_URL", "https://www.youtube.com/feeds/videos.xml"),
+		WebsiteAccess:  getEnv("WEBSITE_ACCESS", "https://ytst.flgr.fr"),
+		AdminToken:     os.Getenv("ADMIN_TOKEN"),
+
+		PublicBaseURL:  strings.TrimRight(getEnv("PUBLIC_BASE_URL", "https://ytst-back.flgr.fr"), "/"),
+		HubURL:         getEnv("HUB_URL", "https://pubsubhubbub.appspot.com/subscribe"),
+		HubVerifyToken: os.Getenv("YTBToken"),
+
+		RSSPollInterval: 30 * time.Minute,
+
+		ShortsMaxDuration: 3 * time.Minute,
+		ShortsProbeURL:    strings.TrimRight(getEnv("SHORTS_PROBE_URL", "https://www.youtube.com"), "/"),
+
+		LivePollInterval: 1 * time.Minute,
+
+		SubscriberMilestones:  []int64{1000, 10000, 100000, 500000, 1000000, 5000000, 10000000, 50000000, 100000000},
+		ChannelViewMilestones: []int64{1000000, 10000000, 100000000, 1000000000, 10000000000},
+		VideoViewMilestones:   []int64{100000, 1000000, 10000000, 100000000, 1000000000},
+	}
+
+	durations := []struct {
+		key    string
+		target *time.Duration
+	}{
+		{"RSS_POLL_INTERVAL", &cfg.RSSPollInterval},
+		{"SHORTS_MAX_DURATION", &cfg.ShortsMaxDuration},
+		{"LIVE_POLL_INTERVAL", &cfg.LivePollInterval},
+	}
+	for _, d := range durations {
+		if v := os.Getenv(d.key); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid %s %q", d.key, v)
+			}
+			*d.target = parsed
+		}
 	}
 
+	// Paliers séparés par des virgules, par exemple MILESTONES_SUBSCRIBERS=10000,100000,1000000
+	ladders := []struct {
+		key    string
+		target *[]int64
+	}{
+		{"MILESTONES_SUBSCRIBERS", &cfg.SubscriberMilestones},
+		{"MILESTONES_CHANNEL_VIEWS", &cfg.ChannelViewMilestones},
+		{"MILESTONES_VIDEO_VIEWS", &cfg.VideoViewMilestones},
+	}
+	for _, l := range ladders {
+		if v := os.Getenv(l.key); v != "" {
+			var ladder []int64
+			for _, part := range strings.Split(v, ",") {
+				threshold, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+				if err != nil || threshold <= 0 {
+					return nil, fmt.Errorf("invalid %s %q", l.key, v)
+				}
+				ladder = append(ladder, threshold)
+			}
+			slices.Sort(ladder)
+			*l.target = ladder
+		}
+	}
+
+	// Chaque environnement a son propre callback : les abonnements (et leurs renouvellements) sont
+	// indexés par callback, staging ne touche donc jamais aux topics de production.
+	cfg.CallbackURL = cfg.PublicBaseURL + CallbackPath
+
 	if cfg.DBUser == "" || cfg.DBPass == "" || cfg.DBName == "" {
 		return nil, fmt.Errorf("missing DB config (DB_USER, DB_PASS, DB_NAME)")
 	}
@@ -1,5 +1,11 @@
 package config
 
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
 type Config struct {
 	DBUser string
 	DBPass string
@@ -8,8 +14,27 @@ type Config struct {
 	DBPort string
 	DBSSL  string
 
-	YouTubeAPIKey string
-	WebsiteAccess string
+	YouTubeAPIKey  string
+	YouTubeAPIURL  string
+	YouTubeFeedURL string
+	WebsiteAccess  string
+	AdminToken     string
+
+	PublicBaseURL  string
+	CallbackURL    string
+	HubURL         string
+	HubVerifyToken string
+
+	RSSPollInterval time.Duration
+
+	ShortsMaxDuration time.Duration
+	ShortsProbeURL    string
+
+	LivePollInterval time.Duration
+
+	SubscriberMilestones  []int64
+	ChannelViewMilestones []int64
+	VideoViewMilestones   []int64
 }
 
 type YouTubeChannel struct {
@@ -51,11 +76,13 @@ type YouTubeVideo struct {
 	Items []struct {
 		ID      string `json:"id"`
 		Snippet struct {
-			Title       string `json:"title"`
-			Description string `json:"description"`
-			PublishedAt string `json:"publishedAt"`
-			ChannelId   string `json:"channelId"`
-			Thumbnails  struct {
+			Title                string `json:"title"`
+			Description          string `json:"description"`
+			PublishedAt          string `json:"publishedAt"`
+			ChannelId            string `json:"channelId"`
+			LiveBroadcastContent string `json:"liveBroadcastContent"`
+			CategoryID           string `json:"categoryId"`
+			Thumbnails           struct {
 				Default struct {
 					URL string `json:"url"`
 				} `json:"default"`
@@ -67,9 +94,17 @@ type YouTubeVideo struct {
 				} `json:"high"`
 			} `json:"thumbnails"`
 		} `json:"snippet"`
+		LiveStreamingDetails *LiveStreamingDetails `json:"liveStreamingDetails"`
 	} `json:"items"`
 }
 
+type LiveStreamingDetails struct {
+	ScheduledStartTime string `json:"scheduledStartTime"`
+	ActualStartTime    string `json:"actualStartTime"`
+	ActualEndTime      string `json:"actualEndTime"`
+	ConcurrentViewers  string `json:"concurrentViewers"`
+}
+
 type YouTubeVideoStats struct {
 	Items []struct {
 		ID         string `json:"id"`
@@ -90,11 +125,66 @@ type YouTubeVideoContentDetails struct {
 	} `json:"items"`
 }
 
+type AtomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type AtomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri"`
+}
+
 type Feed struct {
-	Entry []struct {
-		ChannelId string `xml:"channelId" xml:"http://www.youtube.com/xml/schemas/2015 channelId"`
-		VideoId   string `xml:"videoId" xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
-	} `xml:"entry"`
+	Links        []AtomLink         `xml:"link"`
+	Title        string             `xml:"title"`
+	Updated      string             `xml:"updated"`
+	Entry        []FeedEntry        `xml:"entry"`
+	DeletedEntry []FeedDeletedEntry `xml:"http://purl.org/atompub/tombstones/1.0 deleted-entry"`
+}
+
+type FeedEntry struct {
+	ID        string     `xml:"id"`
+	VideoId   string     `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+	ChannelId string     `xml:"http://www.youtube.com/xml/schemas/2015 channelId"`
+	Title     string     `xml:"title"`
+	Links     []AtomLink `xml:"link"`
+	Author    AtomPerson `xml:"author"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+
+	MediaGroup FeedMediaGroup `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+type FeedMediaGroup struct {
+	Title     string `xml:"http://search.yahoo.com/mrss/ title"`
+	Thumbnail struct {
+		URL string `xml:"url,attr"`
+	} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Community struct {
+		StarRating struct {
+			Count   int64   `xml:"count,attr"`
+			Average float64 `xml:"average,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ starRating"`
+		Statistics struct {
+			Views int64 `xml:"views,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ statistics"`
+	} `xml:"http://search.yahoo.com/mrss/ community"`
+}
+
+type FeedDeletedEntry struct {
+	Ref  string     `xml:"ref,attr"`
+	When string     `xml:"when,attr"`
+	Link AtomLink   `xml:"link"`
+	By   AtomPerson `xml:"http://purl.org/atompub/tombstones/1.0 by"`
+}
+
+func (d FeedDeletedEntry) VideoId() string {
+	return strings.TrimPrefix(d.Ref, "yt:video:")
+}
+
+func (d FeedDeletedEntry) ChannelId() string {
+	return d.By.URI[strings.LastIndex(d.By.URI, "/")+1:]
 }
 
 type Channel struct {
@@ -119,27 +209,529 @@ type ChannelStats struct {
 }
 
 type Video struct {
-	ID           int    `json:"id"`
-	VideoID      string `json:"video_id"`
-	IsShort      bool   `json:"is_short"`
-	ChannelID    string `json:"channel_id"`
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	PublishedAt  string `json:"published_at"`
-	ThumbnailURL string `json:"thumbnail_url"`
-	AddedAt      string `json:"added_at"`
-	Frequency    string `json:"refreshed_frequency"`
+	ID               int      `json:"id"`
+	VideoID          string   `json:"video_id"`
+	IsShort          bool     `json:"is_short"`
+	ChannelID        string   `json:"channel_id"`
+	Title            string   `json:"title"`
+	Description      string   `json:"description"`
+	PublishedAt      string   `json:"published_at"`
+	ThumbnailURL     string   `json:"thumbnail_url"`
+	AddedAt          string   `json:"added_at"`
+	Frequency        string   `json:"refreshed_frequency"`
+	RemovedAt        *string  `json:"removed_at"`
+	DiscoveredVia    string   `json:"discovered_via"`
+	ShortSource      string   `json:"short_source"`
+	ShortConfidence  *float64 `json:"short_confidence"`
+	DurationSeconds  *int     `json:"duration_seconds"`
+	LiveType         string   `json:"live_type"`
+	ScheduledStartAt *string  `json:"scheduled_start_at"`
+	ActualStartAt    *string  `json:"actual_start_at"`
+	ActualEndAt      *string  `json:"actual_end_at"`
+	CategoryID       *string  `json:"category_id"`
+
+	Predictions []VideoPrediction `json:"predictions,omitempty"`
 }
 
 type VideoStats struct {
-	ID            int    `json:"id"`
-	VideoID       string `json:"video_id"`
-	ViewsCount    string `json:"views_count"`
-	LikesCount    string `json:"likes_count"`
-	CommentsCount string `json:"comments_count"`
-	RecordedAt    string `json:"recorded_at"`
+	ID            int      `json:"id"`
+	VideoID       string   `json:"video_id"`
+	ViewsCount    string   `json:"views_count"`
+	LikesCount    *string  `json:"likes_count"`
+	CommentsCount *string  `json:"comments_count"`
+	RatingCount   *int64   `json:"rating_count"`
+	RatingAverage *float64 `json:"rating_average"`
+	Source        string   `json:"source"`
+	RecordedAt    string   `json:"recorded_at"`
 }
 
 type AddChannelRequest struct {
 	ChannelID string `json:"channelId"`
 }
+
+type HubSubscription struct {
+	ID                    int     `json:"id"`
+	ChannelID             string  `json:"channel_id"`
+	Topic                 string  `json:"topic"`
+	Callback              string  `json:"callback"`
+	Secret                string  `json:"-"`
+	RequestedLeaseSeconds *int    `json:"requested_lease_seconds"`
+	GrantedLeaseSeconds   *int    `json:"granted_lease_seconds"`
+	Status                string  `json:"status"`
+	LastError             string  `json:"last_error"`
+	RequestedAt           *string `json:"requested_at"`
+	VerifiedAt            *string `json:"verified_at"`
+	ExpiresAt             *string `json:"expires_at"`
+	CreatedAt             string  `json:"created_at"`
+	Health                string  `json:"health"`
+}
+
+type HubNotification struct {
+	ID          int     `json:"id"`
+	Topic       string  `json:"topic"`
+	Body        string  `json:"body"`
+	Status      string  `json:"status"`
+	Attempts    int     `json:"attempts"`
+	LastError   string  `json:"last_error"`
+	ReceivedAt  string  `json:"received_at"`
+	ProcessedAt *string `json:"processed_at"`
+}
+
+type DiscoveryStats struct {
+	ChannelID string  `json:"channel_id"`
+	Name      string  `json:"name"`
+	Push      int     `json:"push"`
+	Poll      int     `json:"poll"`
+	PushShare float64 `json:"push_share"`
+}
+
+type DurationBucket struct {
+	Label      string `json:"label"`
+	MinSeconds int    `json:"min_seconds"`
+	MaxSeconds *int   `json:"max_seconds"`
+	Count      int    `json:"count"`
+}
+
+type ChannelDurationStats struct {
+	ChannelID      string           `json:"channel_id"`
+	Name           string           `json:"name"`
+	VideoCount     int              `json:"video_count"`
+	AverageSeconds float64          `json:"average_seconds"`
+	MedianSeconds  float64          `json:"median_seconds"`
+	TotalSeconds   int64            `json:"total_seconds"`
+	Buckets        []DurationBucket `json:"buckets"`
+}
+
+type LiveViewersSample struct {
+	ConcurrentViewers int    `json:"concurrent_viewers"`
+	RecordedAt        string `json:"recorded_at"`
+}
+
+type LiveStats struct {
+	VideoID          string              `json:"video_id"`
+	LiveType         string              `json:"live_type"`
+	ScheduledStartAt *string             `json:"scheduled_start_at"`
+	ActualStartAt    *string             `json:"actual_start_at"`
+	ActualEndAt      *string             `json:"actual_end_at"`
+	PeakViewers      *int                `json:"peak_viewers"`
+	PeakAt           *string             `json:"peak_at"`
+	AverageViewers   *float64            `json:"average_viewers"`
+	Samples          int                 `json:"samples"`
+	Timeline         []LiveViewersSample `json:"timeline"`
+}
+
+type VideoSnapshot struct {
+	RecordedAt time.Time
+	Views      int64
+	Likes      *int64
+	Comments   *int64
+	Source     string
+}
+
+type ChannelSnapshot struct {
+	RecordedAt  time.Time
+	Subscribers int64
+	Views       int64
+	Videos      int64
+}
+
+type AnalyticsInterval struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Value        float64   `json:"value"`
+	Delta        float64   `json:"delta"`
+	PerHour      float64   `json:"per_hour"`
+	Acceleration *float64  `json:"acceleration"`
+}
+
+type MetricAnalytics struct {
+	Metric    string              `json:"metric"`
+	Latest    *float64            `json:"latest"`
+	Gain24h   *float64            `json:"gain_24h"`
+	Gain7d    *float64            `json:"gain_7d"`
+	Gain30d   *float64            `json:"gain_30d"`
+	Intervals []AnalyticsInterval `json:"intervals"`
+}
+
+type SeriesAnalytics struct {
+	ID      string            `json:"id"`
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Bucket  string            `json:"bucket"`
+	Metrics []MetricAnalytics `json:"metrics"`
+}
+
+type ForecastModelScore struct {
+	Model      string  `json:"model"`
+	R2         float64 `json:"r2"`
+	AdjustedR2 float64 `json:"adjusted_r2"`
+}
+
+type ForecastPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+	Lower float64   `json:"lower"`
+	Upper float64   `json:"upper"`
+}
+
+type MilestoneETA struct {
+	Target   float64    `json:"target"`
+	Reached  bool       `json:"reached"`
+	ETA      *time.Time `json:"eta"`
+	Earliest *time.Time `json:"earliest"`
+	Latest   *time.Time `json:"latest"`
+}
+
+type MetricForecast struct {
+	Metric      string               `json:"metric"`
+	Model       string               `json:"model"`
+	R2          float64              `json:"r2"`
+	AdjustedR2  float64              `json:"adjusted_r2"`
+	Models      []ForecastModelScore `json:"models"`
+	Latest      float64              `json:"latest"`
+	Projections []ForecastPoint      `json:"projections"`
+	Milestones  []MilestoneETA       `json:"milestones"`
+}
+
+type Forecast struct {
+	ID      string           `json:"id"`
+	Horizon string           `json:"horizon"`
+	Metrics []MetricForecast `json:"metrics"`
+}
+
+type OutlierScore struct {
+	VideoID       string   `json:"video_id"`
+	Title         string   `json:"title"`
+	ChannelID     string   `json:"channel_id"`
+	ChannelName   string   `json:"channel_name"`
+	IsShort       bool     `json:"is_short"`
+	AgeHours      int      `json:"age_hours"`
+	Views         int64    `json:"views"`
+	ChannelMedian float64  `json:"channel_median"`
+	ChannelP10    float64  `json:"channel_p10"`
+	ChannelP90    float64  `json:"channel_p90"`
+	Peers         int      `json:"peers"`
+	Multiplier    *float64 `json:"multiplier"`
+	ComputedAt    string   `json:"computed_at"`
+}
+
+type Outliers struct {
+	AgeHours        int            `json:"age_hours"`
+	OverPerformers  []OutlierScore `json:"over_performers"`
+	UnderPerformers []OutlierScore `json:"under_performers"`
+}
+
+type CurvePoint struct {
+	AgeHours float64 `json:"age_hours"`
+	P10      float64 `json:"p10"`
+	P50      float64 `json:"p50"`
+	P90      float64 `json:"p90"`
+	Videos   int     `json:"videos"`
+}
+
+type PerformanceCurve struct {
+	Format string       `json:"format"`
+	Videos int          `json:"videos"`
+	Points []CurvePoint `json:"points"`
+}
+
+type CurveOverlayPoint struct {
+	AgeHours float64  `json:"age_hours"`
+	Views    float64  `json:"views"`
+	VsMedian *float64 `json:"vs_median"`
+}
+
+type CurveOverlay struct {
+	VideoID string              `json:"video_id"`
+	Format  string              `json:"format"`
+	Points  []CurveOverlayPoint `json:"points"`
+}
+
+type ChannelCurve struct {
+	ChannelID string             `json:"channel_id"`
+	Curves    []PerformanceCurve `json:"curves"`
+	Video     *CurveOverlay      `json:"video,omitempty"`
+}
+
+type VideoPrediction struct {
+	HorizonHours   int     `json:"horizon_hours"`
+	BasisAgeHours  int     `json:"basis_age_hours"`
+	BasisViews     int64   `json:"basis_views"`
+	PredictedViews int64   `json:"predicted_views"`
+	LowerViews     int64   `json:"lower_views"`
+	UpperViews     int64   `json:"upper_views"`
+	Method         string  `json:"method"`
+	Comparables    int     `json:"comparables"`
+	PredictedAt    string  `json:"predicted_at"`
+	ActualViews    *int64  `json:"actual_views"`
+	ResolvedAt     *string `json:"resolved_at"`
+}
+
+type PredictionAccuracy struct {
+	HorizonHours          int      `json:"horizon_hours"`
+	BasisAgeHours         int      `json:"basis_age_hours"`
+	Method                string   `json:"method"`
+	Resolved              int      `json:"resolved"`
+	MeanAbsPercentError   *float64 `json:"mean_abs_percent_error"`
+	MedianAbsPercentError *float64 `json:"median_abs_percent_error"`
+	WithinBand            *float64 `json:"within_band"`
+}
+
+type Anomaly struct {
+	ID         int     `json:"id"`
+	ChannelID  string  `json:"channel_id"`
+	VideoID    *string `json:"video_id"`
+	VideoTitle *string `json:"video_title"`
+	Metric     string  `json:"metric"`
+	Kind       string  `json:"kind"`
+	Severity   string  `json:"severity"`
+	StartedAt  string  `json:"started_at"`
+	EndedAt    string  `json:"ended_at"`
+	Observed   float64 `json:"observed"`
+	Expected   float64 `json:"expected"`
+	Score      float64 `json:"score"`
+	DetectedAt string  `json:"detected_at"`
+}
+
+type EngagementSnapshot struct {
+	RecordedAt       time.Time `json:"recorded_at"`
+	Views            int64     `json:"views"`
+	Likes            *int64    `json:"likes"`
+	Comments         *int64    `json:"comments"`
+	LikesPer1k       *float64  `json:"likes_per_1k"`
+	CommentsPer1k    *float64  `json:"comments_per_1k"`
+	LikeCommentRatio *float64  `json:"like_comment_ratio"`
+}
+
+type VideoEngagement struct {
+	VideoID            string               `json:"video_id"`
+	Snapshots          []EngagementSnapshot `json:"snapshots"`
+	Latest             *EngagementSnapshot  `json:"latest"`
+	LikesPer1kTrend    *float64             `json:"likes_per_1k_trend"`
+	CommentsPer1kTrend *float64             `json:"comments_per_1k_trend"`
+}
+
+type EngagementSummary struct {
+	Format                 string   `json:"format"`
+	Videos                 int      `json:"videos"`
+	MedianLikesPer1k       *float64 `json:"median_likes_per_1k"`
+	MedianCommentsPer1k    *float64 `json:"median_comments_per_1k"`
+	MedianLikeCommentRatio *float64 `json:"median_like_comment_ratio"`
+}
+
+type ChannelEngagement struct {
+	ChannelID string              `json:"channel_id"`
+	Name      string              `json:"name"`
+	Uploads   int                 `json:"uploads"`
+	Formats   []EngagementSummary `json:"formats"`
+}
+
+type EngagementRanking struct {
+	Rank      int               `json:"rank"`
+	ChannelID string            `json:"channel_id"`
+	Name      string            `json:"name"`
+	Summary   EngagementSummary `json:"summary"`
+}
+
+type WeeklyUploads struct {
+	WeekStart string `json:"week_start"`
+	Uploads   int    `json:"uploads"`
+}
+
+type UploadSlot struct {
+	Weekday string `json:"weekday"`
+	Hour    int    `json:"hour"`
+	Uploads int    `json:"uploads"`
+}
+
+type NextUploadWindow struct {
+	Earliest      time.Time   `json:"earliest"`
+	Expected      time.Time   `json:"expected"`
+	Latest        time.Time   `json:"latest"`
+	PreferredSlot *UploadSlot `json:"preferred_slot"`
+	Overdue       bool        `json:"overdue"`
+}
+
+type UploadCadence struct {
+	ChannelID      string            `json:"channel_id"`
+	Timezone       string            `json:"timezone"`
+	Uploads        int               `json:"uploads"`
+	Weekly         []WeeklyUploads   `json:"weekly"`
+	Heatmap        [7][24]int        `json:"heatmap"`
+	MedianGapHours *float64          `json:"median_gap_hours"`
+	P90GapHours    *float64          `json:"p90_gap_hours"`
+	StreakWeeks    int               `json:"streak_weeks"`
+	LastUploadAt   *time.Time        `json:"last_upload_at"`
+	SilenceHours   *float64          `json:"silence_hours"`
+	UnusualSilence bool              `json:"unusual_silence"`
+	NextUpload     *NextUploadWindow `json:"next_upload"`
+}
+
+type SilentChannel struct {
+	ChannelID      string    `json:"channel_id"`
+	Name           string    `json:"name"`
+	LastUploadAt   time.Time `json:"last_upload_at"`
+	SilenceHours   float64   `json:"silence_hours"`
+	MedianGapHours float64   `json:"median_gap_hours"`
+	P90GapHours    float64   `json:"p90_gap_hours"`
+}
+
+type DailySummaryRow struct {
+	Date              string `json:"date"`
+	SubscribersChange *int64 `json:"subscribers_change"`
+	Subscribers       int64  `json:"subscribers"`
+	ViewsChange       *int64 `json:"views_change"`
+	Views             int64  `json:"views"`
+	Uploads           int    `json:"uploads"`
+	Estimated         bool   `json:"estimated"`
+}
+
+type DailyAverage struct {
+	Days              int     `json:"days"`
+	SubscribersChange float64 `json:"subscribers_change"`
+	ViewsChange       float64 `json:"views_change"`
+	Uploads           float64 `json:"uploads"`
+}
+
+type DailyProjection struct {
+	Days        int    `json:"days"`
+	Date        string `json:"date"`
+	Subscribers int64  `json:"subscribers"`
+	Views       int64  `json:"views"`
+}
+
+type ChannelDailySummary struct {
+	ChannelID   string            `json:"channel_id"`
+	Days        []DailySummaryRow `json:"days"`
+	Average30d  *DailyAverage     `json:"average_30d"`
+	Projections []DailyProjection `json:"projections"`
+}
+
+type ComparisonMetric struct {
+	Metric string     `json:"metric"`
+	Values []*float64 `json:"values"`
+	Latest *float64   `json:"latest"`
+	Delta  *float64   `json:"delta"`
+	Rank   *int       `json:"rank"`
+}
+
+type ComparisonItem struct {
+	ID      string             `json:"id"`
+	Name    string             `json:"name"`
+	Metrics []ComparisonMetric `json:"metrics"`
+}
+
+// Times (chaînes, grille calendaire) ou AgesHours (vidéos, âge depuis la publication)
+// donnent l'abscisse commune des valeurs.
+type Comparison struct {
+	Kind      string           `json:"kind"`
+	Bucket    string           `json:"bucket"`
+	Times     []time.Time      `json:"times,omitempty"`
+	AgesHours []float64        `json:"ages_hours,omitempty"`
+	Items     []ComparisonItem `json:"items"`
+}
+
+type ChannelLeaderboardEntry struct {
+	Rank            int      `json:"rank"`
+	ChannelID       string   `json:"channel_id"`
+	Name            string   `json:"name"`
+	Country         *string  `json:"country"`
+	Subscribers     *int64   `json:"subscribers"`
+	Views           *int64   `json:"views"`
+	SubscribersGain *int64   `json:"subscribers_gain"`
+	ViewsGain       *int64   `json:"views_gain"`
+	GrowthPercent   *float64 `json:"growth_percent"`
+	LikesPer1k      *float64 `json:"likes_per_1k"`
+}
+
+type VideoLeaderboardEntry struct {
+	Rank          int      `json:"rank"`
+	VideoID       string   `json:"video_id"`
+	Title         string   `json:"title"`
+	ChannelID     string   `json:"channel_id"`
+	ChannelName   string   `json:"channel_name"`
+	IsShort       bool     `json:"is_short"`
+	CategoryID    *string  `json:"category_id"`
+	PublishedAt   string   `json:"published_at"`
+	Views         *int64   `json:"views"`
+	ViewsGain     *int64   `json:"views_gain"`
+	GrowthPercent *float64 `json:"growth_percent"`
+	LikesPer1k    *float64 `json:"likes_per_1k"`
+}
+
+type LeaderboardPage struct {
+	Metric   string `json:"metric"`
+	Window   string `json:"window"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+	Total    int    `json:"total"`
+}
+
+type ChannelLeaderboard struct {
+	LeaderboardPage
+	Entries []ChannelLeaderboardEntry `json:"entries"`
+}
+
+type VideoLeaderboard struct {
+	LeaderboardPage
+	Entries []VideoLeaderboardEntry `json:"entries"`
+}
+
+type LeaderboardFilters struct {
+	Country  string
+	Format   string
+	Category string
+}
+
+type MilestoneEvent struct {
+	ID          int     `json:"id"`
+	ChannelID   string  `json:"channel_id"`
+	ChannelName string  `json:"channel_name"`
+	VideoID     *string `json:"video_id"`
+	VideoTitle  *string `json:"video_title"`
+	Metric      string  `json:"metric"`
+	Threshold   int64   `json:"threshold"`
+	Value       int64   `json:"value"`
+	ReachedAt   string  `json:"reached_at"`
+	DetectedAt  string  `json:"detected_at"`
+}
+
+type MilestoneAtomFeed struct {
+	XMLName xml.Name             `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string               `xml:"id"`
+	Title   string               `xml:"title"`
+	Updated string               `xml:"updated"`
+	Links   []AtomLink           `xml:"link"`
+	Entries []MilestoneAtomEntry `xml:"entry"`
+}
+
+type MilestoneAtomEntry struct {
+	ID      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Links   []AtomLink `xml:"link"`
+	Summary string     `xml:"summary"`
+}
+
+type FormatBreakdown struct {
+	Format         string            `json:"format"`
+	Videos         int               `json:"videos"`
+	TotalViews     int64             `json:"total_views"`
+	ViewShare      *float64          `json:"view_share"`
+	MedianViews24h *float64          `json:"median_views_24h"`
+	MedianViews7d  *float64          `json:"median_views_7d"`
+	Engagement     EngagementSummary `json:"engagement"`
+}
+
+type MonthlyFormatUploads struct {
+	Month      string   `json:"month"`
+	Shorts     int      `json:"shorts"`
+	Long       int      `json:"long"`
+	ShortShare *float64 `json:"short_share"`
+}
+
+type ChannelFormatBreakdown struct {
+	ChannelID string                 `json:"channel_id"`
+	Formats   []FormatBreakdown      `json:"formats"`
+	Monthly   []MonthlyFormatUploads `json:"monthly"`
+}
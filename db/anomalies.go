@@ -0,0 +1,74 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+	"ytst-back/config"
+)
+
+// Un même événement peut être redétecté à chaque passage : il n'est enregistré qu'une fois,
+// puis mis à jour tant qu'il est en cours (blocage qui se prolonge).
+// Le booléen indique si l'événement vient d'être créé.
+func SaveAnomaly(db *sql.DB, channelDBID int, videoDBID *int, metric string, kind string, severity string,
+	startedAt time.Time, endedAt time.Time, observed float64, expected float64, score float64) (bool, error) {
+	query := `
+		INSERT INTO anomalies (channel_id, video_id, metric, kind, severity, started_at, ended_at, observed, expected, score)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (channel_id, COALESCE(video_id, 0), metric, kind, started_at) DO UPDATE SET
+			ended_at = EXCLUDED.ended_at,
+			observed = EXCLUDED.observed,
+			expected = EXCLUDED.expected,
+			score = EXCLUDED.score,
+			severity = EXCLUDED.severity
+		RETURNING (xmax = 0);
+	`
+	var inserted bool
+	err := db.QueryRow(query, channelDBID, videoDBID, metric, kind, severity, startedAt, endedAt, observed, expected, score).Scan(&inserted)
+	return inserted, err
+}
+
+func Anomalies(db *sql.DB, channelID string, limit int) ([]config.Anomaly, error) {
+	query := `
+		SELECT a.id, c.channel_id, v.video_id, v.title, a.metric, a.kind, a.severity,
+			a.started_at, a.ended_at, a.observed, a.expected, a.score, a.detected_at
+		FROM anomalies a
+		JOIN channels c ON c.id = a.channel_id
+		LEFT JOIN videos v ON v.id = a.video_id
+		WHERE c.channel_id = $1
+		ORDER BY a.started_at DESC
+		LIMIT $2;
+	`
+	rows, err := db.Query(query, channelID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	anomalies := []config.Anomaly{}
+	for rows.Next() {
+		var a config.Anomaly
+		if err := rows.Scan(
+			&a.ID,
+			&a.ChannelID,
+			&a.VideoID,
+			&a.VideoTitle,
+			&a.Metric,
+			&a.Kind,
+			&a.Severity,
+			&a.StartedAt,
+			&a.EndedAt,
+			&a.Observed,
+			&a.Expected,
+			&a.Score,
+			&a.DetectedAt,
+		); err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return anomalies, nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+func RefreshChannelDailyStats(db *sql.DB) error {
+	_, err := db.Exec(`REFRESH MATERIALIZED VIEW CONCURRENTLY channel_daily_stats`)
+	return err
+}
+
+type ChannelDailyStat struct {
+	Day         time.Time
+	Subscribers int64
+	Views       int64
+	Videos      int64
+}
+
+func ChannelDailyStats(db *sql.DB, channelID string) ([]ChannelDailyStat, error) {
+	query := `
+		SELECT d.day, COALESCE(d.subscribers_count, 0), COALESCE(d.views_count, 0), COALESCE(d.videos_count, 0)
+		FROM channel_daily_stats d
+		JOIN channels c ON c.id = d.channel_id
+		WHERE c.channel_id = $1
+		ORDER BY d.day;
+	`
+	rows, err := db.Query(query, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []ChannelDailyStat
+	for rows.Next() {
+		var s ChannelDailyStat
+		if err := rows.Scan(&s.Day, &s.Subscribers, &s.Views, &s.Videos); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Nombre de vidéos publiées par jour (clé AAAA-MM-JJ).
+func DailyUploads(db *sql.DB, channelID string) (map[string]int, error) {
+	query := `
+		SELECT v.published_at::date, COUNT(*)
+		FROM videos v
+		JOIN channels c ON c.id = v.channel_id
+		WHERE c.channel_id = $1 AND v.removed_at IS NULL
+		GROUP BY 1;
+	`
+	rows, err := db.Query(query, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	uploads := map[string]int{}
+	for rows.Next() {
+		var day time.Time
+		var count int
+		if err := rows.Scan(&day, &count); err != nil {
+			return nil, err
+		}
+		uploads[day.Format("2006-01-02")] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
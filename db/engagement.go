@@ -0,0 +1,98 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+type VideoEngagementRow struct {
+	ChannelID  string
+	Name       string
+	VideoID    string
+	IsShort    bool
+	RankAll    int
+	RankFormat int
+	Views      int64
+	Likes      *int64
+	Comments   *int64
+}
+
+// Dernier relevé API des lastN dernières vidéos publiées de chaque chaîne, toutes vidéos
+// confondues (RankAll) et par format (RankFormat). Les relevés RSS n'ont ni likes ni commentaires.
+func LatestVideoEngagement(db *sql.DB, channelID string, lastN int) ([]VideoEngagementRow, error) {
+	query := `
+		SELECT c.channel_id, c.name, v.video_id, v.is_short, v.rank_all, v.rank_format,
+			s.views_count, s.likes_count, s.comments_count
+		FROM (
+			SELECT id, video_id, channel_id, COALESCE(is_short, FALSE) AS is_short,
+				ROW_NUMBER() OVER (PARTITION BY channel_id ORDER BY published_at DESC) AS rank_all,
+				ROW_NUMBER() OVER (PARTITION BY channel_id, COALESCE(is_short, FALSE) ORDER BY published_at DESC) AS rank_format
+			FROM videos
+			WHERE removed_at IS NULL
+		) v
+		JOIN channels c ON c.id = v.channel_id
+		JOIN LATERAL (
+			SELECT views_count, likes_count, comments_count FROM video_stats
+			WHERE video_id = v.id AND source = 'api' AND views_count IS NOT NULL
+			ORDER BY recorded_at DESC
+			LIMIT 1
+		) s ON TRUE
+		WHERE (v.rank_all <= $2 OR v.rank_format <= $2) AND ($1 = '' OR c.channel_id = $1)
+		ORDER BY c.channel_id, v.rank_all;
+	`
+	rows, err := db.Query(query, channelID, lastN)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []VideoEngagementRow
+	for rows.Next() {
+		var r VideoEngagementRow
+		if err := rows.Scan(&r.ChannelID, &r.Name, &r.VideoID, &r.IsShort, &r.RankAll, &r.RankFormat, &r.Views, &r.Likes, &r.Comments); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type MonthlyUploads struct {
+	Month   time.Time
+	IsShort bool
+	Uploads int
+}
+
+func MonthlyUploadsByFormat(db *sql.DB, channelID string) ([]MonthlyUploads, error) {
+	query := `
+		SELECT date_trunc('month', v.published_at), COALESCE(v.is_short, FALSE), COUNT(*)
+		FROM videos v
+		JOIN channels c ON c.id = v.channel_id
+		WHERE c.channel_id = $1 AND v.removed_at IS NULL
+		GROUP BY 1, 2
+		ORDER BY 1;
+	`
+	rows, err := db.Query(query, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uploads []MonthlyUploads
+	for rows.Next() {
+		var u MonthlyUploads
+		if err := rows.Scan(&u.Month, &u.IsShort, &u.Uploads); err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+	"ytst-back/config"
+)
+
+type ChannelFeedPoll struct {
+	ChannelDBID  int
+	ChannelID    string
+	AddedAt      time.Time
+	ETag         string
+	LastModified string
+}
+
+func ChannelFeedPolls(db *sql.DB) ([]ChannelFeedPoll, error) {
+	query := `
+		SELECT c.id, c.channel_id, c.added_at, COALESCE(p.etag, ''), COALESCE(p.last_modified, '')
+		FROM channels c
+		LEFT JOIN channel_feed_polls p ON p.channel_id = c.id
+		ORDER BY p.last_polled_at ASC NULLS FIRST;
+	`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var polls []ChannelFeedPoll
+	for rows.Next() {
+		var poll ChannelFeedPoll
+		if err := rows.Scan(&poll.ChannelDBID, &poll.ChannelID, &poll.AddedAt, &poll.ETag, &poll.LastModified); err != nil {
+			return nil, err
+		}
+		polls = append(polls, poll)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return polls, nil
+}
+
+func SaveChannelFeedPoll(db *sql.DB, channelDBID int, etag string, lastModified string, status int) error {
+	query := `
+		INSERT INTO channel_feed_polls (channel_id, etag, last_modified, last_status, last_polled_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (channel_id) DO UPDATE SET
+			etag = EXCLUDED.etag,
+			last_modified = EXCLUDED.last_modified,
+			last_status = EXCLUDED.last_status,
+			last_polled_at = NOW();
+	`
+	_, err := db.Exec(query, channelDBID, etag, lastModified, status)
+	return err
+}
+
+func DiscoveryStats(db *sql.DB) ([]config.DiscoveryStats, error) {
+	query := `
+		SELECT c.channel_id, c.name,
+			COUNT(v.id) FILTER (WHERE v.discovered_via = 'push'),
+			COUNT(v.id) FILTER (WHERE v.discovered_via = 'poll')
+		FROM channels c
+		LEFT JOIN videos v ON v.channel_id = c.id
+		GROUP BY c.id
+		ORDER BY c.name;
+	`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []config.DiscoveryStats
+	for rows.Next() {
+		var s config.DiscoveryStats
+		if err := rows.Scan(&s.ChannelID, &s.Name, &s.Push, &s.Poll); err != nil {
+			return nil, err
+		}
+		if total := s.Push + s.Poll; total > 0 {
+			s.PushShare = float64(s.Push) / float64(total)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Enregistre les statistiques publiques du flux RSS pour une vidéo suivie publiée récemment.
+// Retourne l'identifiant interne de la vidéo, ou 0 si aucun relevé n'a été enregistré.
+func InsertFeedVideoStats(db *sql.DB, videoID string, views int64, ratingCount int64, ratingAverage float64, maxAge time.Duration) (int, error) {
+	query := `
+		INSERT INTO video_stats (video_id, views_count, rating_count, rating_average, source)
+		SELECT id, $2, $3, $4, 'rss' FROM videos
+		WHERE video_id = $1 AND removed_at IS NULL AND published_at > NOW() - make_interval(secs => $5)
+		RETURNING video_id;
+	`
+	var videoDBID int
+	err := db.QueryRow(query, videoID, views, ratingCount, ratingAverage, maxAge.Seconds()).Scan(&videoDBID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return videoDBID, err
+}
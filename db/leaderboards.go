@@ -0,0 +1,176 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+	"ytst-back/config"
+)
+
+// Colonnes de tri autorisées, par métrique exposée.
+var (
+	ChannelLeaderboardColumns = map[string]string{
+		"subscribers":      "subscribers",
+		"subscribers_gain": "subscribers_gain",
+		"views":            "views",
+		"views_gain":       "views_gain",
+		"growth":           "growth",
+		"engagement":       "likes_per_1k",
+	}
+	VideoLeaderboardColumns = map[string]string{
+		"views":      "views",
+		"views_gain": "views_gain",
+		"growth":     "growth",
+		"engagement": "likes_per_1k",
+	}
+)
+
+// Les gains comparent le dernier relevé au dernier relevé antérieur à (dernier relevé - window).
+// L'engagement d'une chaîne est la médiane des likes pour 1 000 vues de ses 20 dernières vidéos.
+func ChannelLeaderboard(db *sql.DB, column string, window time.Duration, filters config.LeaderboardFilters, limit int, offset int) ([]config.ChannelLeaderboardEntry, int, error) {
+	query := `
+		WITH ranked AS (
+			SELECT c.channel_id, c.name, c.country,
+				l.subscribers_count::bigint AS subscribers,
+				l.views_count AS views,
+				(l.subscribers_count - p.subscribers_count)::bigint AS subscribers_gain,
+				l.views_count - p.views_count AS views_gain,
+				(l.subscribers_count - p.subscribers_count) * 100.0 / NULLIF(p.subscribers_count, 0) AS growth,
+				e.likes_per_1k
+			FROM channels c
+			JOIN LATERAL (
+				SELECT subscribers_count, views_count, recorded_at FROM channel_stats
+				WHERE channel_id = c.id ORDER BY recorded_at DESC LIMIT 1
+			) l ON TRUE
+			LEFT JOIN LATERAL (
+				SELECT subscribers_count, views_count FROM channel_stats
+				WHERE channel_id = c.id AND recorded_at <= l.recorded_at - make_interval(secs => $1)
+				ORDER BY recorded_at DESC LIMIT 1
+			) p ON TRUE
+			LEFT JOIN LATERAL (
+				SELECT PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY s.likes_count * 1000.0 / s.views_count) AS likes_per_1k
+				FROM (
+					SELECT id FROM videos WHERE channel_id = c.id AND removed_at IS NULL
+					ORDER BY published_at DESC LIMIT 20
+				) v
+				JOIN LATERAL (
+					SELECT likes_count, views_count FROM video_stats
+					WHERE video_id = v.id AND source = 'api' AND views_count > 0 AND likes_count IS NOT NULL
+					ORDER BY recorded_at DESC LIMIT 1
+				) s ON TRUE
+			) e ON TRUE
+			WHERE ($2 = '' OR c.country = $2)
+		)
+		SELECT channel_id, name, country, subscribers, views, subscribers_gain, views_gain, growth, likes_per_1k, COUNT(*) OVER ()
+		FROM ranked
+		ORDER BY ` + column + ` DESC NULLS LAST, channel_id
+		LIMIT $3 OFFSET $4;
+	`
+	rows, err := db.Query(query, window.Seconds(), filters.Country, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []config.ChannelLeaderboardEntry{}
+	total := 0
+	for rows.Next() {
+		var e config.ChannelLeaderboardEntry
+		if err := rows.Scan(
+			&e.ChannelID,
+			&e.Name,
+			&e.Country,
+			&e.Subscribers,
+			&e.Views,
+			&e.SubscribersGain,
+			&e.ViewsGain,
+			&e.GrowthPercent,
+			&e.LikesPer1k,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		e.Rank = offset + len(entries) + 1
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// Une vidéo publiée depuis moins que window part de 0 vue.
+func VideoLeaderboard(db *sql.DB, column string, window time.Duration, filters config.LeaderboardFilters, limit int, offset int) ([]config.VideoLeaderboardEntry, int, error) {
+	query := `
+		WITH ranked AS (
+			SELECT v.video_id, v.title, c.channel_id, c.name AS channel_name, COALESCE(v.is_short, FALSE) AS is_short,
+				v.category_id, v.published_at,
+				l.views_count AS views,
+				l.views_count - base.views_count AS views_gain,
+				(l.views_count - base.views_count) * 100.0 / NULLIF(base.views_count, 0) AS growth,
+				e.likes_count * 1000.0 / NULLIF(e.views_count, 0) AS likes_per_1k
+			FROM videos v
+			JOIN channels c ON c.id = v.channel_id
+			JOIN LATERAL (
+				SELECT views_count, recorded_at FROM video_stats
+				WHERE video_id = v.id AND views_count IS NOT NULL ORDER BY recorded_at DESC LIMIT 1
+			) l ON TRUE
+			LEFT JOIN LATERAL (
+				SELECT views_count FROM video_stats
+				WHERE video_id = v.id AND views_count IS NOT NULL AND recorded_at <= l.recorded_at - make_interval(secs => $1)
+				ORDER BY recorded_at DESC LIMIT 1
+			) p ON TRUE
+			CROSS JOIN LATERAL (
+				SELECT COALESCE(p.views_count, CASE WHEN v.published_at >= l.recorded_at - make_interval(secs => $1) THEN 0 END) AS views_count
+			) base
+			LEFT JOIN LATERAL (
+				SELECT likes_count, views_count FROM video_stats
+				WHERE video_id = v.id AND source = 'api' AND likes_count IS NOT NULL
+				ORDER BY recorded_at DESC LIMIT 1
+			) e ON TRUE
+			WHERE v.removed_at IS NULL
+				AND ($2 = '' OR c.country = $2)
+				AND ($3 = '' OR ($3 = 'short') = COALESCE(v.is_short, FALSE))
+				AND ($4 = '' OR v.category_id = $4)
+		)
+		SELECT video_id, title, channel_id, channel_name, is_short, category_id, published_at,
+			views, views_gain, growth, likes_per_1k, COUNT(*) OVER ()
+		FROM ranked
+		ORDER BY ` + column + ` DESC NULLS LAST, video_id
+		LIMIT $5 OFFSET $6;
+	`
+	rows, err := db.Query(query, window.Seconds(), filters.Country, filters.Format, filters.Category, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []config.VideoLeaderboardEntry{}
+	total := 0
+	for rows.Next() {
+		var e config.VideoLeaderboardEntry
+		if err := rows.Scan(
+			&e.VideoID,
+			&e.Title,
+			&e.ChannelID,
+			&e.ChannelName,
+			&e.IsShort,
+			&e.CategoryID,
+			&e.PublishedAt,
+			&e.Views,
+			&e.ViewsGain,
+			&e.GrowthPercent,
+			&e.LikesPer1k,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		e.Rank = offset + len(entries) + 1
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
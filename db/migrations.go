@@ -57,6 +57,181 @@ func RunMigrations(db *sql.DB) error {
 		FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
 	);`
 
+	createHubSubscriptionsTable := `
+	CREATE TABLE IF NOT EXISTS hub_subscriptions (
+		id SERIAL PRIMARY KEY,
+		channel_id VARCHAR(255) NOT NULL,
+		topic TEXT NOT NULL,
+		callback TEXT NOT NULL,
+		secret TEXT NOT NULL DEFAULT '',
+		requested_lease_seconds INT,
+		granted_lease_seconds INT,
+		status VARCHAR(32) NOT NULL DEFAULT 'pending',
+		last_error TEXT NOT NULL DEFAULT '',
+		requested_at TIMESTAMP,
+		verified_at TIMESTAMP,
+		expires_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT NOW(),
+		UNIQUE (topic, callback)
+	);`
+
+	createHubNotificationsTable := `
+	CREATE TABLE IF NOT EXISTS hub_notifications (
+		id SERIAL PRIMARY KEY,
+		topic TEXT NOT NULL DEFAULT '',
+		body TEXT NOT NULL,
+		status VARCHAR(16) NOT NULL DEFAULT 'queued',
+		attempts INT NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		received_at TIMESTAMP DEFAULT NOW(),
+		processed_at TIMESTAMP
+	);`
+
+	createProcessedVideoUpdatesTable := `
+	CREATE TABLE IF NOT EXISTS processed_video_updates (
+		video_id VARCHAR(255) NOT NULL,
+		updated TEXT NOT NULL,
+		notification_id INT,
+		processed_at TIMESTAMP DEFAULT NOW(),
+		PRIMARY KEY (video_id, updated),
+		FOREIGN KEY (notification_id) REFERENCES hub_notifications(id) ON DELETE SET NULL
+	);`
+
+	alterVideoStatsTable := []string{
+		`ALTER TABLE video_stats ADD COLUMN IF NOT EXISTS source VARCHAR(8) NOT NULL DEFAULT 'api';`,
+		`ALTER TABLE video_stats ADD COLUMN IF NOT EXISTS rating_count BIGINT;`,
+		`ALTER TABLE video_stats ADD COLUMN IF NOT EXISTS rating_average REAL;`,
+	}
+
+	createLiveViewersTable := `
+	CREATE TABLE IF NOT EXISTS live_viewers (
+		id SERIAL PRIMARY KEY,
+		video_id INT NOT NULL,
+		concurrent_viewers INT NOT NULL,
+		recorded_at TIMESTAMP DEFAULT NOW(),
+		FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+	);`
+
+	createChannelFeedPollsTable := `
+	CREATE TABLE IF NOT EXISTS channel_feed_polls (
+		channel_id INT PRIMARY KEY,
+		etag TEXT NOT NULL DEFAULT '',
+		last_modified TEXT NOT NULL DEFAULT '',
+		last_status INT,
+		last_polled_at TIMESTAMP,
+		FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE
+	);`
+
+	createVideoOutlierScoresTable := `
+	CREATE TABLE IF NOT EXISTS video_outlier_scores (
+		video_id INT NOT NULL,
+		age_hours INT NOT NULL,
+		views BIGINT NOT NULL,
+		channel_median DOUBLE PRECISION NOT NULL,
+		channel_p10 DOUBLE PRECISION NOT NULL,
+		channel_p90 DOUBLE PRECISION NOT NULL,
+		peers INT NOT NULL,
+		multiplier DOUBLE PRECISION,
+		computed_at TIMESTAMP DEFAULT NOW(),
+		PRIMARY KEY (video_id, age_hours),
+		FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+	);`
+
+	// Format de la vidéo au moment du calcul : les pairs sont de la même chaîne et du même format.
+	alterVideoOutlierScoresTable := `ALTER TABLE video_outlier_scores ADD COLUMN IF NOT EXISTS is_short BOOLEAN NOT NULL DEFAULT FALSE;`
+
+	createVideoPredictionsTable := `
+	CREATE TABLE IF NOT EXISTS video_predictions (
+		video_id INT NOT NULL,
+		horizon_hours INT NOT NULL,
+		basis_age_hours INT NOT NULL,
+		basis_views BIGINT NOT NULL,
+		predicted_views BIGINT NOT NULL,
+		lower_views BIGINT NOT NULL,
+		upper_views BIGINT NOT NULL,
+		method VARCHAR(8) NOT NULL,
+		comparables INT NOT NULL,
+		predicted_at TIMESTAMP DEFAULT NOW(),
+		actual_views BIGINT,
+		resolved_at TIMESTAMP,
+		PRIMARY KEY (video_id, horizon_hours, basis_age_hours),
+		FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+	);`
+
+	createAnomaliesTable := `
+	CREATE TABLE IF NOT EXISTS anomalies (
+		id SERIAL PRIMARY KEY,
+		channel_id INT NOT NULL,
+		video_id INT,
+		metric VARCHAR(16) NOT NULL,
+		kind VARCHAR(16) NOT NULL,
+		severity VARCHAR(8) NOT NULL,
+		started_at TIMESTAMP NOT NULL,
+		ended_at TIMESTAMP NOT NULL,
+		observed DOUBLE PRECISION NOT NULL,
+		expected DOUBLE PRECISION NOT NULL,
+		score DOUBLE PRECISION NOT NULL,
+		detected_at TIMESTAMP DEFAULT NOW(),
+		FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE,
+		FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+	);`
+
+	createAnomaliesUniqueIndex := `
+	CREATE UNIQUE INDEX IF NOT EXISTS anomalies_event_idx
+		ON anomalies (channel_id, COALESCE(video_id, 0), metric, kind, started_at);`
+
+	// Dernier relevé de chaque jour : les doublons d'une même journée sont écartés.
+	createChannelDailyStatsView := `
+	CREATE MATERIALIZED VIEW IF NOT EXISTS channel_daily_stats AS
+	SELECT DISTINCT ON (channel_id, recorded_at::date)
+		channel_id,
+		recorded_at::date AS day,
+		subscribers_count,
+		views_count,
+		videos_count,
+		recorded_at
+	FROM channel_stats
+	ORDER BY channel_id, recorded_at::date, recorded_at DESC;`
+
+	createChannelDailyStatsIndex := `
+	CREATE UNIQUE INDEX IF NOT EXISTS channel_daily_stats_day_idx ON channel_daily_stats (channel_id, day);`
+
+	createStatsIndexes := []string{
+		`CREATE INDEX IF NOT EXISTS channel_stats_channel_recorded_idx ON channel_stats (channel_id, recorded_at);`,
+		`CREATE INDEX IF NOT EXISTS video_stats_video_recorded_idx ON video_stats (video_id, recorded_at);`,
+	}
+
+	createMilestoneEventsTable := `
+	CREATE TABLE IF NOT EXISTS milestone_events (
+		id SERIAL PRIMARY KEY,
+		channel_id INT NOT NULL,
+		video_id INT,
+		metric VARCHAR(16) NOT NULL,
+		threshold BIGINT NOT NULL,
+		value BIGINT NOT NULL,
+		reached_at TIMESTAMP NOT NULL,
+		detected_at TIMESTAMP DEFAULT NOW(),
+		FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE,
+		FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+	);`
+
+	createMilestoneEventsUniqueIndex := `
+	CREATE UNIQUE INDEX IF NOT EXISTS milestone_events_threshold_idx
+		ON milestone_events (channel_id, COALESCE(video_id, 0), metric, threshold);`
+
+	alterVideosTable := []string{
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS removed_at TIMESTAMP;`,
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS discovered_via VARCHAR(8) NOT NULL DEFAULT 'push';`,
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS short_source VARCHAR(16) NOT NULL DEFAULT 'legacy';`,
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS short_confidence REAL;`,
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS duration_seconds INT;`,
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS live_type VARCHAR(16) NOT NULL DEFAULT 'none';`,
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS scheduled_start_at TIMESTAMP;`,
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS actual_start_at TIMESTAMP;`,
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS actual_end_at TIMESTAMP;`,
+		`ALTER TABLE videos ADD COLUMN IF NOT EXISTS category_id VARCHAR(8);`,
+	}
+
 	if _, err := db.Exec(createChannelsTable); err != nil {
 		return fmt.Errorf("erreur lors de la création de la table channels : %w", err)
 	}
@@ -73,6 +248,80 @@ func RunMigrations(db *sql.DB) error {
 		return fmt.Errorf("erreur lors de la création de la table video_stats : %w", err)
 	}
 
+	for _, query := range alterVideosTable {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("erreur lors de la mise à jour de la table videos : %w", err)
+		}
+	}
+
+	for _, query := range alterVideoStatsTable {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("erreur lors de la mise à jour de la table video_stats : %w", err)
+		}
+	}
+
+	for _, query := range createStatsIndexes {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("erreur lors de la création des index des statistiques : %w", err)
+		}
+	}
+
+	if _, err := db.Exec(createHubSubscriptionsTable); err != nil {
+		return fmt.Errorf("erreur lors de la création de la table hub_subscriptions : %w", err)
+	}
+
+	if _, err := db.Exec(createHubNotificationsTable); err != nil {
+		return fmt.Errorf("erreur lors de la création de la table hub_notifications : %w", err)
+	}
+
+	if _, err := db.Exec(createProcessedVideoUpdatesTable); err != nil {
+		return fmt.Errorf("erreur lors de la création de la table processed_video_updates : %w", err)
+	}
+
+	if _, err := db.Exec(createChannelFeedPollsTable); err != nil {
+		return fmt.Errorf("erreur lors de la création de la table channel_feed_polls : %w", err)
+	}
+
+	if _, err := db.Exec(createLiveViewersTable); err != nil {
+		return fmt.Errorf("erreur lors de la création de la table live_viewers : %w", err)
+	}
+
+	if _, err := db.Exec(createVideoOutlierScoresTable); err != nil {
+		return fmt.Errorf("erreur lors de la création de la table video_outlier_scores : %w", err)
+	}
+
+	if _, err := db.Exec(alterVideoOutlierScoresTable); err != nil {
+		return fmt.Errorf("erreur lors de la mise à jour de la table video_outlier_scores : %w", err)
+	}
+
+	if _, err := db.Exec(createVideoPredictionsTable); err != nil {
+		return fmt.Errorf("erreur lors de la création de la table video_predictions : %w", err)
+	}
+
+	if _, err := db.Exec(createAnomaliesTable); err != nil {
+		return fmt.Errorf("erreur lors de la création de la table anomalies : %w", err)
+	}
+
+	if _, err := db.Exec(createAnomaliesUniqueIndex); err != nil {
+		return fmt.Errorf("erreur lors de la création de l'index de la table anomalies : %w", err)
+	}
+
+	if _, err := db.Exec(createChannelDailyStatsView); err != nil {
+		return fmt.Errorf("erreur lors de la création de la vue channel_daily_stats : %w", err)
+	}
+
+	if _, err := db.Exec(createChannelDailyStatsIndex); err != nil {
+		return fmt.Errorf("erreur lors de la création de l'index de la vue channel_daily_stats : %w", err)
+	}
+
+	if _, err := db.Exec(createMilestoneEventsTable); err != nil {
+		return fmt.Errorf("erreur lors de la création de la table milestone_events : %w", err)
+	}
+
+	if _, err := db.Exec(createMilestoneEventsUniqueIndex); err != nil {
+		return fmt.Errorf("erreur lors de la création de l'index de la table milestone_events : %w", err)
+	}
+
 	log.Println("Les tables ont été créées avec succès !")
 	return nil
 }
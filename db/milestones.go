@@ -0,0 +1,120 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+	"ytst-back/config"
+)
+
+type MilestoneSample struct {
+	RecordedAt  time.Time
+	Subscribers *int64
+	Views       *int64
+}
+
+// Les deux derniers relevés d'une chaîne, du plus récent au plus ancien.
+func LastChannelSamples(db *sql.DB, channelDBID int) ([]MilestoneSample, error) {
+	return milestoneSamples(db, `
+		SELECT recorded_at, subscribers_count, views_count FROM channel_stats
+		WHERE channel_id = $1 ORDER BY recorded_at DESC, id DESC LIMIT 2`, channelDBID)
+}
+
+func LastVideoSamples(db *sql.DB, videoDBID int) ([]MilestoneSample, error) {
+	return milestoneSamples(db, `
+		SELECT recorded_at, NULL::bigint, views_count FROM video_stats
+		WHERE video_id = $1 ORDER BY recorded_at DESC, id DESC LIMIT 2`, videoDBID)
+}
+
+func milestoneSamples(db *sql.DB, query string, id int) ([]MilestoneSample, error) {
+	rows, err := db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []MilestoneSample
+	for rows.Next() {
+		var s MilestoneSample
+		if err := rows.Scan(&s.RecordedAt, &s.Subscribers, &s.Views); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// Un palier n'est enregistré qu'une fois : la première date à laquelle il a été constaté.
+func SaveChannelMilestone(db *sql.DB, channelDBID int, metric string, threshold int64, value int64, reachedAt time.Time) (bool, error) {
+	res, err := db.Exec(`
+		INSERT INTO milestone_events (channel_id, metric, threshold, value, reached_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING`,
+		channelDBID, metric, threshold, value, reachedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func SaveVideoMilestone(db *sql.DB, videoDBID int, metric string, threshold int64, value int64, reachedAt time.Time) (bool, error) {
+	res, err := db.Exec(`
+		INSERT INTO milestone_events (channel_id, video_id, metric, threshold, value, reached_at)
+		SELECT channel_id, id, $2, $3, $4, $5 FROM videos WHERE id = $1
+		ON CONFLICT DO NOTHING`,
+		videoDBID, metric, threshold, value, reachedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Événements les plus récents, pour une chaîne ou toutes si channelID est vide.
+func MilestoneEvents(db *sql.DB, channelID string, limit int) ([]config.MilestoneEvent, error) {
+	query := `
+		SELECT m.id, c.channel_id, c.name, v.video_id, v.title, m.metric, m.threshold, m.value, m.reached_at, m.detected_at
+		FROM milestone_events m
+		JOIN channels c ON c.id = m.channel_id
+		LEFT JOIN videos v ON v.id = m.video_id
+		WHERE $1 = '' OR c.channel_id = $1
+		ORDER BY m.reached_at DESC, m.id DESC
+		LIMIT $2;
+	`
+	rows, err := db.Query(query, channelID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []config.MilestoneEvent{}
+	for rows.Next() {
+		var e config.MilestoneEvent
+		if err := rows.Scan(
+			&e.ID,
+			&e.ChannelID,
+			&e.ChannelName,
+			&e.VideoID,
+			&e.VideoTitle,
+			&e.Metric,
+			&e.Threshold,
+			&e.Value,
+			&e.ReachedAt,
+			&e.DetectedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
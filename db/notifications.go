@@ -0,0 +1,116 @@
+package db
+
+import (
+	"database/sql"
+	"ytst-back/config"
+)
+
+const hubNotificationColumns = `id, topic, body, status, attempts, last_error, received_at, processed_at`
+
+func scanHubNotification(row rowScanner) (config.HubNotification, error) {
+	var notification config.HubNotification
+	err := row.Scan(
+		&notification.ID,
+		&notification.Topic,
+		&notification.Body,
+		&notification.Status,
+		&notification.Attempts,
+		&notification.LastError,
+		&notification.ReceivedAt,
+		&notification.ProcessedAt,
+	)
+	return notification, err
+}
+
+func SaveHubNotification(db *sql.DB, topic string, body []byte) (int, error) {
+	var id int
+	err := db.QueryRow(
+		`INSERT INTO hub_notifications (topic, body) VALUES ($1, $2) RETURNING id`,
+		topic, string(body),
+	).Scan(&id)
+	return id, err
+}
+
+func HubNotification(db *sql.DB, id int) (config.HubNotification, error) {
+	return scanHubNotification(db.QueryRow("SELECT "+hubNotificationColumns+" FROM hub_notifications WHERE id = $1", id))
+}
+
+func HubNotifications(db *sql.DB, status string, limit int) ([]config.HubNotification, error) {
+	rows, err := db.Query(
+		"SELECT "+hubNotificationColumns+" FROM hub_notifications WHERE $1 = '' OR status = $1 ORDER BY received_at DESC LIMIT $2",
+		status, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []config.HubNotification
+	for rows.Next() {
+		notification, err := scanHubNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// Notifications en attente (ou en échec, dans la limite des tentatives) reçues avant le délai de grâce.
+func PendingHubNotificationIDs(db *sql.DB, maxAttempts int, graceSeconds float64) ([]int, error) {
+	query := `
+		SELECT id FROM hub_notifications
+		WHERE (status = 'queued' OR (status = 'failed' AND attempts < $1))
+			AND received_at < NOW() - make_interval(secs => $2)
+		ORDER BY received_at ASC;
+	`
+	rows, err := db.Query(query, maxAttempts, graceSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func FinishHubNotification(db *sql.DB, id int, status string, lastError string) error {
+	_, err := db.Exec(
+		`UPDATE hub_notifications SET status = $2, last_error = $3, attempts = attempts + 1, processed_at = NOW() WHERE id = $1`,
+		id, status, lastError,
+	)
+	return err
+}
+
+// Réserve le couple (vidéo, horodatage <updated>) : false si déjà traité.
+func ClaimVideoUpdate(db *sql.DB, videoID string, updated string, notificationID int) (bool, error) {
+	res, err := db.Exec(
+		`INSERT INTO processed_video_updates (video_id, updated, notification_id) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		videoID, updated, notificationID,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func ReleaseVideoUpdate(db *sql.DB, videoID string, updated string) error {
+	_, err := db.Exec(`DELETE FROM processed_video_updates WHERE video_id = $1 AND updated = $2`, videoID, updated)
+	return err
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"database/sql"
+	"ytst-back/config"
+)
+
+func SaveOutlierScore(db *sql.DB, videoDBID int, isShort bool, ageHours int, views int64, median float64, p10 float64, p90 float64, peers int, multiplier *float64) error {
+	query := `
+		INSERT INTO video_outlier_scores (video_id, age_hours, views, channel_median, channel_p10, channel_p90, peers, multiplier, is_short, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		ON CONFLICT (video_id, age_hours) DO UPDATE SET
+			views = EXCLUDED.views,
+			channel_median = EXCLUDED.channel_median,
+			channel_p10 = EXCLUDED.channel_p10,
+			channel_p90 = EXCLUDED.channel_p90,
+			peers = EXCLUDED.peers,
+			multiplier = EXCLUDED.multiplier,
+			is_short = EXCLUDED.is_short,
+			computed_at = NOW();
+	`
+	_, err := db.Exec(query, videoDBID, ageHours, views, median, p10, p90, peers, multiplier, isShort)
+	return err
+}
+
+// Scores à un âge donné, du plus fort multiplicateur au plus faible (ou l'inverse si ascending).
+func OutlierScores(db *sql.DB, ageHours int, limit int, ascending bool) ([]config.OutlierScore, error) {
+	order := "DESC"
+	if ascending {
+		order = "ASC"
+	}
+	query := `
+		SELECT v.video_id, v.title, c.channel_id, c.name, o.is_short, o.age_hours, o.views,
+			o.channel_median, o.channel_p10, o.channel_p90, o.peers, o.multiplier, o.computed_at
+		FROM video_outlier_scores o
+		JOIN videos v ON v.id = o.video_id
+		JOIN channels c ON c.id = v.channel_id
+		WHERE o.age_hours = $1 AND o.multiplier IS NOT NULL AND v.removed_at IS NULL
+		ORDER BY o.multiplier ` + order + `
+		LIMIT $2;
+	`
+	rows, err := db.Query(query, ageHours, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := []config.OutlierScore{}
+	for rows.Next() {
+		var s config.OutlierScore
+		if err := rows.Scan(
+			&s.VideoID,
+			&s.Title,
+			&s.ChannelID,
+			&s.ChannelName,
+			&s.IsShort,
+			&s.AgeHours,
+			&s.Views,
+			&s.ChannelMedian,
+			&s.ChannelP10,
+			&s.ChannelP90,
+			&s.Peers,
+			&s.Multiplier,
+			&s.ComputedAt,
+		); err != nil {
+			return nil, err
+		}
+		scores = append(scores, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"database/sql"
+	"ytst-back/config"
+)
+
+// Une prédiction déjà confrontée à la réalité n'est plus écrasée.
+func SaveVideoPrediction(db *sql.DB, videoDBID int, p config.VideoPrediction) error {
+	query := `
+		INSERT INTO video_predictions (video_id, horizon_hours, basis_age_hours, basis_views, predicted_views,
+			lower_views, upper_views, method, comparables, predicted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		ON CONFLICT (video_id, horizon_hours, basis_age_hours) DO UPDATE SET
+			basis_views = EXCLUDED.basis_views,
+			predicted_views = EXCLUDED.predicted_views,
+			lower_views = EXCLUDED.lower_views,
+			upper_views = EXCLUDED.upper_views,
+			method = EXCLUDED.method,
+			comparables = EXCLUDED.comparables,
+			predicted_at = NOW()
+		WHERE video_predictions.actual_views IS NULL;
+	`
+	_, err := db.Exec(query, videoDBID, p.HorizonHours, p.BasisAgeHours, p.BasisViews, p.PredictedViews,
+		p.LowerViews, p.UpperViews, p.Method, p.Comparables)
+	return err
+}
+
+func ResolveVideoPredictions(db *sql.DB, videoDBID int, horizonHours int, actualViews int64) error {
+	_, err := db.Exec(
+		`UPDATE video_predictions SET actual_views = $3, resolved_at = NOW()
+		WHERE video_id = $1 AND horizon_hours = $2 AND actual_views IS NULL`,
+		videoDBID, horizonHours, actualViews,
+	)
+	return err
+}
+
+// Prédiction la plus récente (âge de base le plus élevé) pour chaque horizon.
+func LatestVideoPredictions(db *sql.DB, videoID string) ([]config.VideoPrediction, error) {
+	query := `
+		SELECT DISTINCT ON (p.horizon_hours) p.horizon_hours, p.basis_age_hours, p.basis_views, p.predicted_views,
+			p.lower_views, p.upper_views, p.method, p.comparables, p.predicted_at, p.actual_views, p.resolved_at
+		FROM video_predictions p
+		JOIN videos v ON v.id = p.video_id
+		WHERE v.video_id = $1
+		ORDER BY p.horizon_hours, p.basis_age_hours DESC;
+	`
+	rows, err := db.Query(query, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var predictions []config.VideoPrediction
+	for rows.Next() {
+		var p config.VideoPrediction
+		if err := rows.Scan(
+			&p.HorizonHours,
+			&p.BasisAgeHours,
+			&p.BasisViews,
+			&p.PredictedViews,
+			&p.LowerViews,
+			&p.UpperViews,
+			&p.Method,
+			&p.Comparables,
+			&p.PredictedAt,
+			&p.ActualViews,
+			&p.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+		predictions = append(predictions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return predictions, nil
+}
+
+func PredictionAccuracy(db *sql.DB) ([]config.PredictionAccuracy, error) {
+	query := `
+		SELECT horizon_hours, basis_age_hours, method, COUNT(*),
+			AVG(ABS(predicted_views - actual_views)::float / NULLIF(actual_views, 0)),
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY ABS(predicted_views - actual_views)::float / NULLIF(actual_views, 0)),
+			AVG(CASE WHEN actual_views BETWEEN lower_views AND upper_views THEN 1.0 ELSE 0.0 END)
+		FROM video_predictions
+		WHERE actual_views IS NOT NULL
+		GROUP BY horizon_hours, basis_age_hours, method
+		ORDER BY horizon_hours, basis_age_hours, method;
+	`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accuracy := []config.PredictionAccuracy{}
+	for rows.Next() {
+		var a config.PredictionAccuracy
+		if err := rows.Scan(
+			&a.HorizonHours,
+			&a.BasisAgeHours,
+			&a.Method,
+			&a.Resolved,
+			&a.MeanAbsPercentError,
+			&a.MedianAbsPercentError,
+			&a.WithinBand,
+		); err != nil {
+			return nil, err
+		}
+		accuracy = append(accuracy, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return accuracy, nil
+}
@@ -7,6 +7,41 @@ import (
 	"github.com/lib/pq"
 )
 
+const videoColumns = `id, video_id, is_short, channel_id, title, description, published_at, thumbnail_url,
+	added_at, refreshed_frequency, removed_at, discovered_via, short_source, short_confidence, duration_seconds,
+	live_type, scheduled_start_at, actual_start_at, actual_end_at, category_id`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanVideo(row rowScanner) (config.Video, error) {
+	var video config.Video
+	err := row.Scan(
+		&video.ID,
+		&video.VideoID,
+		&video.IsShort,
+		&video.ChannelID,
+		&video.Title,
+		&video.Description,
+		&video.PublishedAt,
+		&video.ThumbnailURL,
+		&video.AddedAt,
+		&video.Frequency,
+		&video.RemovedAt,
+		&video.DiscoveredVia,
+		&video.ShortSource,
+		&video.ShortConfidence,
+		&video.DurationSeconds,
+		&video.LiveType,
+		&video.ScheduledStartAt,
+		&video.ActualStartAt,
+		&video.ActualEndAt,
+		&video.CategoryID,
+	)
+	return video, err
+}
+
 func AreChannelsInBDD(db *sql.DB, channelIDs []string) (map[string]bool, error) {
 	rows, err := db.Query("SELECT channel_id FROM channels WHERE channel_id = ANY($1)", pq.StringArray(channelIDs))
 	if err != nil {
@@ -97,7 +132,7 @@ func ChannelStats(db *sql.DB, channelID string) ([]config.ChannelStats, error) {
 	return statsList, nil
 }
 
-func VideosFromChannel(db *sql.DB, channelID string) ([]config.Video, error) {
+func VideosFromChannel(db *sql.DB, channelID string, minDuration *int, maxDuration *int) ([]config.Video, error) {
 	var id int
 	err := db.QueryRow("SELECT id FROM channels WHERE channel_id = $1", channelID).Scan(&id)
 	if err != nil {
@@ -105,26 +140,18 @@ func VideosFromChannel(db *sql.DB, channelID string) ([]config.Video, error) {
 	}
 
 	var videos []config.Video
-	rows, err := db.Query("SELECT * FROM videos WHERE channel_id = $1", id)
+	rows, err := db.Query(`SELECT `+videoColumns+` FROM videos
+		WHERE channel_id = $1
+			AND ($2::int IS NULL OR duration_seconds >= $2)
+			AND ($3::int IS NULL OR duration_seconds <= $3)`, id, minDuration, maxDuration)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var video config.Video
-		if err := rows.Scan(
-			&video.ID,
-			&video.VideoID,
-			&video.IsShort,
-			&video.ChannelID,
-			&video.Title,
-			&video.Description,
-			&video.PublishedAt,
-			&video.ThumbnailURL,
-			&video.AddedAt,
-			&video.Frequency,
-		); err != nil {
+		video, err := scanVideo(rows)
+		if err != nil {
 			return nil, err
 		}
 		videos = append(videos, video)
@@ -137,19 +164,7 @@ func VideosFromChannel(db *sql.DB, channelID string) ([]config.Video, error) {
 }
 
 func VideoInfo(db *sql.DB, videoID string) (config.Video, error) {
-	var video config.Video
-	err := db.QueryRow("SELECT * FROM videos WHERE video_id = $1", videoID).Scan(
-		&video.ID,
-		&video.VideoID,
-		&video.IsShort,
-		&video.ChannelID,
-		&video.Title,
-		&video.Description,
-		&video.PublishedAt,
-		&video.ThumbnailURL,
-		&video.AddedAt,
-		&video.Frequency,
-	)
+	video, err := scanVideo(db.QueryRow("SELECT "+videoColumns+" FROM videos WHERE video_id = $1", videoID))
 	if err != nil {
 		return video, err
 	}
@@ -165,7 +180,9 @@ func VideoStats(db *sql.DB, videoID string) ([]config.VideoStats, error) {
 	}
 
 	var statsList []config.VideoStats
-	rows, err := db.Query("SELECT * FROM video_stats WHERE video_id = $1 ORDER BY recorded_at ASC", id)
+	rows, err := db.Query(`
+		SELECT id, video_id, views_count, likes_count, comments_count, rating_count, rating_average, source, recorded_at
+		FROM video_stats WHERE video_id = $1 ORDER BY recorded_at ASC`, id)
 	if err != nil {
 		return nil, err
 	}
@@ -179,6 +196,9 @@ func VideoStats(db *sql.DB, videoID string) ([]config.VideoStats, error) {
 			&stats.ViewsCount,
 			&stats.LikesCount,
 			&stats.CommentsCount,
+			&stats.RatingCount,
+			&stats.RatingAverage,
+			&stats.Source,
 			&stats.RecordedAt,
 		); err != nil {
 			return nil, err
@@ -227,26 +247,15 @@ func RecuperateLastFollowedChannels(db *sql.DB) ([]config.Channel, error) {
 
 func RecuperateLastFollowedVideos(db *sql.DB) ([]config.Video, error) {
 	var videos []config.Video
-	rows, err := db.Query("SELECT * FROM videos ORDER BY added_at DESC LIMIT 10")
+	rows, err := db.Query("SELECT " + videoColumns + " FROM videos ORDER BY added_at DESC LIMIT 10")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var video config.Video
-		if err := rows.Scan(
-			&video.ID,
-			&video.VideoID,
-			&video.IsShort,
-			&video.ChannelID,
-			&video.Title,
-			&video.Description,
-			&video.PublishedAt,
-			&video.ThumbnailURL,
-			&video.AddedAt,
-			&video.Frequency,
-		); err != nil {
+		video, err := scanVideo(rows)
+		if err != nil {
 			return nil, err
 		}
 		videos = append(videos, video)
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+	"ytst-back/config"
+)
+
+func VideoSnapshots(db *sql.DB, videoID string) ([]config.VideoSnapshot, error) {
+	query := `
+		SELECT s.recorded_at, COALESCE(s.views_count, 0), s.likes_count, s.comments_count, s.source
+		FROM video_stats s
+		JOIN videos v ON v.id = s.video_id
+		WHERE v.video_id = $1
+		ORDER BY s.recorded_at ASC;
+	`
+	rows, err := db.Query(query, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []config.VideoSnapshot
+	for rows.Next() {
+		var s config.VideoSnapshot
+		if err := rows.Scan(&s.RecordedAt, &s.Views, &s.Likes, &s.Comments, &s.Source); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func ChannelSnapshots(db *sql.DB, channelID string) ([]config.ChannelSnapshot, error) {
+	query := `
+		SELECT s.recorded_at, COALESCE(s.subscribers_count, 0), COALESCE(s.views_count, 0), COALESCE(s.videos_count, 0)
+		FROM channel_stats s
+		JOIN channels c ON c.id = s.channel_id
+		WHERE c.channel_id = $1
+		ORDER BY s.recorded_at ASC;
+	`
+	rows, err := db.Query(query, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []config.ChannelSnapshot
+	for rows.Next() {
+		var s config.ChannelSnapshot
+		if err := rows.Scan(&s.RecordedAt, &s.Subscribers, &s.Views, &s.Videos); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+type VideoViewHistory struct {
+	VideoDBID   int
+	VideoID     string
+	ChannelDBID int
+	ChannelID   string
+	IsShort     bool
+	PublishedAt time.Time
+	RecordedAt  []time.Time
+	Views       []int64
+}
+
+// Historique des vues de toutes les vidéos non supprimées, ou de celles d'une chaîne si channelID n'est pas vide.
+// Si source n'est pas vide, seuls les relevés de cette source ('api' ou 'rss') sont retenus.
+func VideoViewHistories(db *sql.DB, channelID string, source string) ([]VideoViewHistory, error) {
+	return videoViewHistories(db, `($1 = '' OR c.channel_id = $1) AND ($2 = '' OR s.source = $2)`, channelID, source)
+}
+
+// Historique des vues d'une seule vidéo, toutes sources confondues.
+func VideoViewHistoryOf(db *sql.DB, videoID string) (VideoViewHistory, error) {
+	histories, err := videoViewHistories(db, `v.video_id = $1`, videoID)
+	if err != nil {
+		return VideoViewHistory{}, err
+	}
+	if len(histories) == 0 {
+		return VideoViewHistory{}, sql.ErrNoRows
+	}
+	return histories[0], nil
+}
+
+func videoViewHistories(db *sql.DB, condition string, args ...interface{}) ([]VideoViewHistory, error) {
+	query := `
+		SELECT v.id, v.video_id, c.id, c.channel_id, COALESCE(v.is_short, FALSE), v.published_at, s.recorded_at, s.views_count
+		FROM videos v
+		JOIN channels c ON c.id = v.channel_id
+		JOIN video_stats s ON s.video_id = v.id
+		WHERE v.removed_at IS NULL AND s.views_count IS NOT NULL AND ` + condition + `
+		ORDER BY v.id, s.recorded_at;
+	`
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var histories []VideoViewHistory
+	for rows.Next() {
+		var h VideoViewHistory
+		var recordedAt time.Time
+		var views int64
+		if err := rows.Scan(&h.VideoDBID, &h.VideoID, &h.ChannelDBID, &h.ChannelID, &h.IsShort, &h.PublishedAt, &recordedAt, &views); err != nil {
+			return nil, err
+		}
+		if n := len(histories); n == 0 || histories[n-1].VideoDBID != h.VideoDBID {
+			histories = append(histories, h)
+		}
+		last := &histories[len(histories)-1]
+		last.RecordedAt = append(last.RecordedAt, recordedAt)
+		last.Views = append(last.Views, views)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+type TrackedChannel struct {
+	DBID      int
+	ChannelID string
+}
+
+func TrackedChannels(db *sql.DB) ([]TrackedChannel, error) {
+	rows, err := db.Query(`SELECT id, channel_id FROM channels ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels []TrackedChannel
+	for rows.Next() {
+		var c TrackedChannel
+		if err := rows.Scan(&c.DBID, &c.ChannelID); err != nil {
+			return nil, err
+		}
+		channels = append(channels, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+type UploadTime struct {
+	ChannelID   string
+	Name        string
+	PublishedAt time.Time
+}
+
+// Dates de publication triées par chaîne, pour une chaîne ou toutes si channelID est vide.
+func UploadTimes(db *sql.DB, channelID string) ([]UploadTime, error) {
+	query := `
+		SELECT c.channel_id, c.name, v.published_at
+		FROM videos v
+		JOIN channels c ON c.id = v.channel_id
+		WHERE v.removed_at IS NULL AND ($1 = '' OR c.channel_id = $1)
+		ORDER BY c.channel_id, v.published_at;
+	`
+	rows, err := db.Query(query, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uploads []UploadTime
+	for rows.Next() {
+		var u UploadTime
+		if err := rows.Scan(&u.ChannelID, &u.Name, &u.PublishedAt); err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+	"ytst-back/config"
+)
+
+// Le secret existant est conservé lors d'un renouvellement pour ne pas invalider les notifications en vol.
+func SaveHubSubscriptionRequest(db *sql.DB, channelID string, topic string, callback string, secret string, leaseSeconds int) (string, error) {
+	query := `
+		INSERT INTO hub_subscriptions (channel_id, topic, callback, secret, requested_lease_seconds, status, last_error, requested_at)
+		VALUES ($1, $2, $3, $4, $5, 'pending', '', NOW())
+		ON CONFLICT (topic, callback) DO UPDATE SET
+			secret = CASE WHEN hub_subscriptions.secret = '' THEN EXCLUDED.secret ELSE hub_subscriptions.secret END,
+			requested_lease_seconds = EXCLUDED.requested_lease_seconds,
+			status = 'pending',
+			last_error = '',
+			requested_at = NOW()
+		RETURNING secret;
+	`
+	var savedSecret string
+	err := db.QueryRow(query, channelID, topic, callback, secret, leaseSeconds).Scan(&savedSecret)
+	return savedSecret, err
+}
+
+func HubSubscriptionSecret(db *sql.DB, topic string, callback string) (string, error) {
+	var secret string
+	err := db.QueryRow(
+		`SELECT secret FROM hub_subscriptions WHERE topic = $1 AND callback = $2`,
+		topic, callback,
+	).Scan(&secret)
+	return secret, err
+}
+
+func HubSubscriptionStatus(db *sql.DB, topic string, callback string) (string, error) {
+	var status string
+	err := db.QueryRow(
+		`SELECT status FROM hub_subscriptions WHERE topic = $1 AND callback = $2`,
+		topic, callback,
+	).Scan(&status)
+	return status, err
+}
+
+func SetHubSubscriptionStatus(db *sql.DB, topic string, callback string, status string, message string) error {
+	_, err := db.Exec(
+		`UPDATE hub_subscriptions SET status = $3, last_error = $4 WHERE topic = $1 AND callback = $2`,
+		topic, callback, status, message,
+	)
+	return err
+}
+
+func MarkHubSubscriptionVerified(db *sql.DB, topic string, callback string, leaseSeconds int) error {
+	query := `
+		UPDATE hub_subscriptions SET
+			status = 'active',
+			last_error = '',
+			granted_lease_seconds = $3,
+			verified_at = NOW(),
+			expires_at = NOW() + make_interval(secs => $3)
+		WHERE topic = $1 AND callback = $2;
+	`
+	_, err := db.Exec(query, topic, callback, leaseSeconds)
+	return err
+}
+
+// Chaînes suivies sans abonnement pour ce callback, ou dont l'abonnement expire dans la fenêtre.
+// Un abonnement refusé est redemandé une fois le délai denialBackoff écoulé.
+func ChannelsWithSubscriptionToRenew(db *sql.DB, callback string, window time.Duration, denialBackoff time.Duration) ([]string, error) {
+	query := `
+		SELECT c.channel_id FROM channels c
+		LEFT JOIN hub_subscriptions s ON s.channel_id = c.channel_id AND s.callback = $1
+		WHERE s.id IS NULL
+			OR s.status = 'error'
+			OR (s.secret = '' AND s.status NOT IN ('pending_unsubscribe', 'unsubscribed', 'denied'))
+			OR (s.status = 'pending' AND s.requested_at < NOW() - INTERVAL '1 hour')
+			OR (s.status = 'denied' AND (s.requested_at IS NULL OR s.requested_at < NOW() - make_interval(secs => $3)))
+			OR (s.status = 'active' AND (s.expires_at IS NULL OR s.expires_at < NOW() + make_interval(secs => $2)));
+	`
+	rows, err := db.Query(query, callback, window.Seconds(), denialBackoff.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channelIDs []string
+	for rows.Next() {
+		var channelID string
+		if err := rows.Scan(&channelID); err != nil {
+			return nil, err
+		}
+		channelIDs = append(channelIDs, channelID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return channelIDs, nil
+}
+
+func HubSubscriptions(db *sql.DB, callback string, window time.Duration) ([]config.HubSubscription, error) {
+	query := `
+		SELECT id, channel_id, topic, callback, secret, requested_lease_seconds, granted_lease_seconds,
+			status, last_error, requested_at, verified_at, expires_at, created_at,
+			CASE
+				WHEN status IN ('error', 'denied', 'pending_unsubscribe', 'unsubscribed') THEN status
+				WHEN verified_at IS NULL THEN 'pending'
+				WHEN expires_at < NOW() THEN 'expired'
+				WHEN expires_at < NOW() + make_interval(secs => $2) THEN 'expiring'
+				ELSE 'healthy'
+			END AS health
+		FROM hub_subscriptions
+		WHERE callback = $1
+		ORDER BY expires_at ASC NULLS FIRST;
+	`
+	rows, err := db.Query(query, callback, window.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []config.HubSubscription
+	for rows.Next() {
+		var sub config.HubSubscription
+		if err := rows.Scan(
+			&sub.ID,
+			&sub.ChannelID,
+			&sub.Topic,
+			&sub.Callback,
+			&sub.Secret,
+			&sub.RequestedLeaseSeconds,
+			&sub.GrantedLeaseSeconds,
+			&sub.Status,
+			&sub.LastError,
+			&sub.RequestedAt,
+			&sub.VerifiedAt,
+			&sub.ExpiresAt,
+			&sub.CreatedAt,
+			&sub.Health,
+		); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+	"ytst-back/config"
+)
+
+func UpdateVideoMetadata(db *sql.DB, videoID string, title string, description string, publishedAt string, thumbnailURL string, categoryID string) (string, error) {
+	query := `
+		UPDATE videos SET title = $2, description = $3, published_at = $4, thumbnail_url = $5,
+			category_id = COALESCE(NULLIF($6, ''), category_id), removed_at = NULL
+		WHERE video_id = $1
+		RETURNING id;
+	`
+	var id string
+	err := db.QueryRow(query, videoID, title, description, publishedAt, thumbnailURL, categoryID).Scan(&id)
+	return id, err
+}
+
+func MarkVideoRemoved(db *sql.DB, videoID string, removedAt time.Time) (bool, error) {
+	res, err := db.Exec(`UPDATE videos SET removed_at = $2 WHERE video_id = $1 AND removed_at IS NULL`, videoID, removedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func VideoIDsToClassify(db *sql.DB, all bool) ([]string, error) {
+	rows, err := db.Query(
+		`SELECT video_id FROM videos WHERE removed_at IS NULL AND ($1 OR short_source <> 'probe') ORDER BY id`,
+		all,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videoIDs []string
+	for rows.Next() {
+		var videoID string
+		if err := rows.Scan(&videoID); err != nil {
+			return nil, err
+		}
+		videoIDs = append(videoIDs, videoID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return videoIDs, nil
+}
+
+func UpdateVideoClassification(db *sql.DB, videoID string, durationSeconds *int, isShort bool, source string, confidence float64) error {
+	_, err := db.Exec(
+		`UPDATE videos SET duration_seconds = COALESCE($2, duration_seconds), is_short = $3, short_source = $4, short_confidence = $5 WHERE video_id = $1`,
+		videoID, durationSeconds, isShort, source, confidence,
+	)
+	return err
+}
+
+type VideoDuration struct {
+	ChannelID string
+	Name      string
+	Seconds   int
+}
+
+// Durées connues des vidéos, pour une chaîne ou toutes si channelID est vide.
+func VideoDurations(db *sql.DB, channelID string) ([]VideoDuration, error) {
+	query := `
+		SELECT c.channel_id, c.name, v.duration_seconds
+		FROM videos v
+		JOIN channels c ON c.id = v.channel_id
+		WHERE v.duration_seconds IS NOT NULL AND v.removed_at IS NULL AND ($1 = '' OR c.channel_id = $1)
+		ORDER BY c.channel_id, v.duration_seconds;
+	`
+	rows, err := db.Query(query, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var durations []VideoDuration
+	for rows.Next() {
+		var d VideoDuration
+		if err := rows.Scan(&d.ChannelID, &d.Name, &d.Seconds); err != nil {
+			return nil, err
+		}
+		durations = append(durations, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return durations, nil
+}
+
+type LiveVideo struct {
+	ID              string
+	VideoID         string
+	LiveType        string
+	DurationSeconds *int
+}
+
+// Directs et premières en cours, ou dont le début est proche.
+func LiveVideosToPoll(db *sql.DB) ([]LiveVideo, error) {
+	query := `
+		SELECT id, video_id, live_type, duration_seconds FROM videos
+		WHERE live_type IN ('upcoming', 'live', 'premiere')
+			AND removed_at IS NULL
+			AND (actual_start_at IS NOT NULL OR scheduled_start_at IS NULL OR scheduled_start_at < NOW() + INTERVAL '15 minutes');
+	`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videos []LiveVideo
+	for rows.Next() {
+		var video LiveVideo
+		if err := rows.Scan(&video.ID, &video.VideoID, &video.LiveType, &video.DurationSeconds); err != nil {
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+func UpdateVideoLiveDetails(db *sql.DB, videoID string, liveType string, scheduledStart string, actualStart string, actualEnd string) error {
+	query := `
+		UPDATE videos SET
+			live_type = $2,
+			scheduled_start_at = NULLIF($3, '')::timestamp,
+			actual_start_at = NULLIF($4, '')::timestamp,
+			actual_end_at = NULLIF($5, '')::timestamp
+		WHERE video_id = $1;
+	`
+	_, err := db.Exec(query, videoID, liveType, scheduledStart, actualStart, actualEnd)
+	return err
+}
+
+func InsertLiveViewers(db *sql.DB, id string, concurrentViewers int) error {
+	_, err := db.Exec(`INSERT INTO live_viewers (video_id, concurrent_viewers) VALUES ($1, $2)`, id, concurrentViewers)
+	return err
+}
+
+func LiveStats(db *sql.DB, videoID string) (config.LiveStats, error) {
+	var stats config.LiveStats
+	var id int
+	err := db.QueryRow(
+		`SELECT id, video_id, live_type, scheduled_start_at, actual_start_at, actual_end_at FROM videos WHERE video_id = $1`,
+		videoID,
+	).Scan(&id, &stats.VideoID, &stats.LiveType, &stats.ScheduledStartAt, &stats.ActualStartAt, &stats.ActualEndAt)
+	if err != nil {
+		return stats, err
+	}
+
+	err = db.QueryRow(`
+		SELECT COUNT(*), MAX(concurrent_viewers), AVG(concurrent_viewers),
+			(SELECT recorded_at FROM live_viewers WHERE video_id = $1 ORDER BY concurrent_viewers DESC, recorded_at ASC LIMIT 1)
+		FROM live_viewers WHERE video_id = $1`, id,
+	).Scan(&stats.Samples, &stats.PeakViewers, &stats.AverageViewers, &stats.PeakAt)
+	if err != nil {
+		return stats, err
+	}
+
+	rows, err := db.Query(`SELECT concurrent_viewers, recorded_at FROM live_viewers WHERE video_id = $1 ORDER BY recorded_at ASC`, id)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+
+	stats.Timeline = []config.LiveViewersSample{}
+	for rows.Next() {
+		var sample config.LiveViewersSample
+		if err := rows.Scan(&sample.ConcurrentViewers, &sample.RecordedAt); err != nil {
+			return stats, err
+		}
+		stats.Timeline = append(stats.Timeline, sample)
+	}
+	return stats, rows.Err()
+}
+
+func VideoPublishedAt(db *sql.DB, videoID string) (time.Time, error) {
+	var publishedAt time.Time
+	err := db.QueryRow(`SELECT published_at FROM videos WHERE video_id = $1`, videoID).Scan(&publishedAt)
+	return publishedAt, err
+}
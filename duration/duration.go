@@ -0,0 +1,185 @@
+// Package duration lit et écrit les durées ISO 8601 utilisées par l'API YouTube
+// (contentDetails.duration), par exemple "PT4M13S" ou "P1DT2H" pour les longs directs.
+package duration
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalid = errors.New("durée ISO 8601 invalide")
+	// Les années et les mois n'ont pas de longueur fixe : on refuse de les convertir.
+	ErrUnsupported = errors.New("durée ISO 8601 en années ou mois non supportée")
+	ErrOverflow    = errors.New("durée ISO 8601 trop grande")
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+var (
+	dateDesignators = "WD"
+	dateUnits       = []time.Duration{week, day}
+	timeDesignators = "HMS"
+	timeUnits       = []time.Duration{time.Hour, time.Minute, time.Second}
+)
+
+// Parse convertit une durée de la forme P[nW][nD][T[nH][nM][n[.f]S]].
+func Parse(s string) (time.Duration, error) {
+	if len(s) < 2 || s[0] != 'P' {
+		return 0, fmt.Errorf("%w : %q", ErrInvalid, s)
+	}
+
+	rest := s[1:]
+	designators, units := dateDesignators, dateUnits
+	inTime := false
+	last := -1
+	components := 0
+	var total time.Duration
+
+	for len(rest) > 0 {
+		if rest[0] == 'T' {
+			if inTime || len(rest) == 1 {
+				return 0, fmt.Errorf("%w : %q", ErrInvalid, s)
+			}
+			inTime = true
+			designators, units = timeDesignators, timeUnits
+			last = -1
+			rest = rest[1:]
+			continue
+		}
+
+		i := 0
+		for i < len(rest) && isDigit(rest[i]) {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("%w : %q", ErrInvalid, s)
+		}
+		integer := rest[:i]
+
+		fraction := ""
+		if i < len(rest) && (rest[i] == '.' || rest[i] == ',') {
+			j := i + 1
+			for j < len(rest) && isDigit(rest[j]) {
+				j++
+			}
+			if j == i+1 {
+				return 0, fmt.Errorf("%w : %q", ErrInvalid, s)
+			}
+			fraction = rest[i+1 : j]
+			i = j
+		}
+
+		if i >= len(rest) {
+			return 0, fmt.Errorf("%w : %q", ErrInvalid, s)
+		}
+		designator := rest[i]
+		rest = rest[i+1:]
+
+		index := strings.IndexByte(designators, designator)
+		if index < 0 {
+			if !inTime && (designator == 'Y' || designator == 'M') {
+				return 0, fmt.Errorf("%w : %q", ErrUnsupported, s)
+			}
+			return 0, fmt.Errorf("%w : %q", ErrInvalid, s)
+		}
+		if index <= last {
+			return 0, fmt.Errorf("%w : %q", ErrInvalid, s)
+		}
+		last = index
+
+		// Seules les secondes, dernière composante, peuvent être fractionnaires.
+		if fraction != "" && (!inTime || designator != 'S') {
+			return 0, fmt.Errorf("%w : %q", ErrInvalid, s)
+		}
+
+		value, err := component(integer, fraction, units[index])
+		if err != nil {
+			return 0, fmt.Errorf("%w : %q", err, s)
+		}
+		if total > math.MaxInt64-value {
+			return 0, fmt.Errorf("%w : %q", ErrOverflow, s)
+		}
+		total += value
+		components++
+	}
+
+	if components == 0 {
+		return 0, fmt.Errorf("%w : %q", ErrInvalid, s)
+	}
+	return total, nil
+}
+
+func component(integer string, fraction string, unit time.Duration) (time.Duration, error) {
+	n, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil || n > int64(math.MaxInt64/unit) {
+		return 0, ErrOverflow
+	}
+	value := time.Duration(n) * unit
+
+	if fraction != "" {
+		if len(fraction) > 9 {
+			fraction = fraction[:9]
+		}
+		nanos, err := strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+		if err != nil {
+			return 0, ErrInvalid
+		}
+		if value > math.MaxInt64-time.Duration(nanos) {
+			return 0, ErrOverflow
+		}
+		value += time.Duration(nanos)
+	}
+	return value, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Format produit la forme canonique, en jours au plus (P1DT2H3M4.5S, PT0S pour zéro).
+func Format(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	b.WriteByte('P')
+	if days := d / day; days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		d -= days * day
+	}
+	if d == 0 {
+		return b.String()
+	}
+
+	b.WriteByte('T')
+	if hours := d / time.Hour; hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+		d -= minutes * time.Minute
+	}
+	if d > 0 {
+		seconds := d / time.Second
+		nanos := d - seconds*time.Second
+		if nanos == 0 {
+			fmt.Fprintf(&b, "%dS", seconds)
+		} else {
+			fmt.Fprintf(&b, "%d.%sS", seconds, strings.TrimRight(fmt.Sprintf("%09d", nanos), "0"))
+		}
+	}
+	return b.String()
+}
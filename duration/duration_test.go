@@ -0,0 +1,121 @@
+package duration
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		err  error
+	}{
+		{"PT0S", 0, nil},
+		{"P0D", 0, nil},
+		{"PT59S", 59 * time.Second, nil},
+		{"PT1M59S", time.Minute + 59*time.Second, nil},
+		{"PT3M", 3 * time.Minute, nil},
+		{"PT1H1M", time.Hour + time.Minute, nil},
+		{"PT1H", time.Hour, nil},
+		{"PT10H0M1S", 10*time.Hour + time.Second, nil},
+		{"P1DT2H", 26 * time.Hour, nil},
+		{"P2D", 48 * time.Hour, nil},
+		{"P1W", 7 * 24 * time.Hour, nil},
+		{"P1W2DT3H4M5S", 9*24*time.Hour + 3*time.Hour + 4*time.Minute + 5*time.Second, nil},
+		{"PT1.5S", 1500 * time.Millisecond, nil},
+		{"PT0,25S", 250 * time.Millisecond, nil},
+		{"PT1.1234567891S", time.Second + 123456789, nil},
+		{"PT90M", 90 * time.Minute, nil},
+
+		{"", 0, ErrInvalid},
+		{"P", 0, ErrInvalid},
+		{"PT", 0, ErrInvalid},
+		{"P1DT", 0, ErrInvalid},
+		{"1H", 0, ErrInvalid},
+		{"PT1", 0, ErrInvalid},
+		{"PTH", 0, ErrInvalid},
+		{"PT1S1M", 0, ErrInvalid},
+		{"PT1M1M", 0, ErrInvalid},
+		{"P1H", 0, ErrInvalid},
+		{"PT1D", 0, ErrInvalid},
+		{"PT1.S", 0, ErrInvalid},
+		{"PT1.5M", 0, ErrInvalid},
+		{"P1.5D", 0, ErrInvalid},
+		{"PT1H T1M", 0, ErrInvalid},
+		{"PTT1H", 0, ErrInvalid},
+		{"pt1h", 0, ErrInvalid},
+		{"PT-1S", 0, ErrInvalid},
+
+		{"P1Y", 0, ErrUnsupported},
+		{"P2M", 0, ErrUnsupported},
+
+		{"PT99999999999999999999S", 0, ErrOverflow},
+		{"P9999999999D", 0, ErrOverflow},
+		{"P15000DT2562047H", 0, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.in, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{59 * time.Second, "PT59S"},
+		{time.Hour + time.Minute, "PT1H1M"},
+		{26 * time.Hour, "P1DT2H"},
+		{48 * time.Hour, "P2D"},
+		{1500 * time.Millisecond, "PT1.5S"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.in); got != tt.want {
+			t.Errorf("Format(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"PT4M13S", "P1DT2H", "P0D", "PT1.5S", "P1W", "P1Y", "PT", "PT1S1M"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		d, err := Parse(s)
+		if err != nil {
+			return
+		}
+		if d < 0 {
+			t.Fatalf("Parse(%q) = %v, negative duration", s, d)
+		}
+
+		formatted := Format(d)
+		again, err := Parse(formatted)
+		if err != nil {
+			t.Fatalf("Parse(Format(%v)) = %q: %v", d, formatted, err)
+		}
+		if again != d {
+			t.Fatalf("round trip %q -> %v -> %q -> %v", s, d, formatted, again)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package localhub
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+	"ytst-back/config"
+	"ytst-back/db"
+	"ytst-back/logic"
+	"ytst-back/routes"
+	"ytst-back/youtube"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+)
+
+// Chaîne complète sans réseau : abonnement et vérification via les routes du backend,
+// notification signée, puis création et suppression de la vidéo en base.
+// Nécessite une base Postgres dédiée : LOCALHUB_E2E_DB (et DB_USER, DB_PASS, DB_HOST, DB_PORT).
+func TestIngestionEndToEnd(t *testing.T) {
+	dbName := os.Getenv("LOCALHUB_E2E_DB")
+	if dbName == "" {
+		t.Skip("LOCALHUB_E2E_DB non défini : test de bout en bout ignoré")
+	}
+	gin.SetMode(gin.TestMode)
+
+	hub := New("http://hub.test")
+	hubServer := httptest.NewServer(hub.Handler())
+	defer hubServer.Close()
+
+	// Le callback dépend de l'adresse du backend, connue seulement une fois le serveur démarré.
+	var backend http.Handler
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backend.ServeHTTP(w, r)
+	}))
+	defer backendServer.Close()
+
+	cfg := &config.Config{
+		DBUser:         os.Getenv("DB_USER"),
+		DBPass:         os.Getenv("DB_PASS"),
+		DBName:         dbName,
+		DBHost:         os.Getenv("DB_HOST"),
+		DBPort:         os.Getenv("DB_PORT"),
+		DBSSL:          "disable",
+		YouTubeAPIKey:  "localhub",
+		YouTubeAPIURL:  hubServer.URL + "/youtube/v3",
+		YouTubeFeedURL: hubServer.URL + "/feeds/videos.xml",
+		WebsiteAccess:  "*",
+		PublicBaseURL:  backendServer.URL,
+		CallbackURL:    backendServer.URL + config.CallbackPath,
+		HubURL:         hubServer.URL + "/subscribe",
+		HubVerifyToken: "e2e",
+
+		ShortsMaxDuration: 3 * time.Minute,
+		ShortsProbeURL:    hubServer.URL,
+	}
+
+	dbConn, err := db.Connect(cfg)
+	if err != nil {
+		t.Fatalf("connexion à la base : %v", err)
+	}
+	defer dbConn.Close()
+	if err := db.RunMigrations(dbConn); err != nil {
+		t.Fatalf("migrations : %v", err)
+	}
+
+	youtube.Configure(cfg)
+	logic.Configure(cfg)
+	backend = routes.SetupRoutes(dbConn, cfg)
+	logic.StartNotificationWorker(dbConn)
+
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 36)
+	channelID, videoID := "UCe2e"+suffix, "e2e"+suffix
+
+	resp, err := http.Post(backendServer.URL+"/ytbtst/addChannel", "application/json",
+		bytes.NewBufferString(`{"channelId":"`+channelID+`"}`))
+	if err != nil {
+		t.Fatalf("ajout de la chaîne : %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ajout de la chaîne : statut %d", resp.StatusCode)
+	}
+
+	sub := waitForSubscription(t, hub)
+	if sub.Topic != TopicURL(channelID) || !sub.Signed {
+		t.Fatalf("abonnement inattendu : %+v", sub)
+	}
+	waitFor(t, "abonnement actif en base", func() bool {
+		status, err := db.HubSubscriptionStatus(dbConn, sub.Topic, cfg.CallbackURL)
+		return err == nil && status == "active"
+	})
+
+	deliveries := hub.Upload(Video{VideoID: videoID, ChannelID: channelID, Title: "Bout en bout"})
+	if len(deliveries) != 1 || deliveries[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("notification d'upload refusée : %+v", deliveries)
+	}
+	waitFor(t, "vidéo enregistrée", func() bool {
+		removed, found := videoRemoved(dbConn, videoID)
+		return found && !removed
+	})
+
+	deliveries = hub.Delete(channelID, videoID)
+	if len(deliveries) != 1 || deliveries[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("notification de suppression refusée : %+v", deliveries)
+	}
+	waitFor(t, "vidéo marquée supprimée", func() bool {
+		removed, found := videoRemoved(dbConn, videoID)
+		return found && removed
+	})
+}
+
+func videoRemoved(dbConn *sql.DB, videoID string) (removed bool, found bool) {
+	err := dbConn.QueryRow(`SELECT removed_at IS NOT NULL FROM videos WHERE video_id = $1`, videoID).Scan(&removed)
+	return removed, err == nil
+}
+
+func waitFor(t *testing.T, what string, condition func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("délai dépassé : %s", what)
+}
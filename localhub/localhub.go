@@ -0,0 +1,509 @@
+// Package localhub est une doublure locale du hub PubSubHubbub de Google et des
+// quelques appels de l'API YouTube utilisés par l'ingestion, pour tester toute la
+// chaîne (abonnement, vérification, notification signée) sans accès réseau.
+package localhub
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	topicPrefix         = "https://www.youtube.com/xml/feeds/videos.xml?channel_id="
+	defaultLeaseSeconds = 864000
+)
+
+type Subscription struct {
+	Topic        string    `json:"topic"`
+	Callback     string    `json:"callback"`
+	Secret       string    `json:"-"`
+	Signed       bool      `json:"signed"`
+	LeaseSeconds int       `json:"lease_seconds"`
+	VerifiedAt   time.Time `json:"verified_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type Video struct {
+	VideoID      string    `json:"video_id"`
+	ChannelID    string    `json:"channel_id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Duration     string    `json:"duration"`
+	PublishedAt  time.Time `json:"published_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	ViewCount    int64     `json:"view_count"`
+	LikeCount    int64     `json:"like_count"`
+	CommentCount int64     `json:"comment_count"`
+	Short        bool      `json:"short"`
+	Deleted      bool      `json:"deleted"`
+}
+
+type Delivery struct {
+	Callback   string `json:"callback"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
+
+type Hub struct {
+	PublicURL string
+	Client    *http.Client
+
+	mu            sync.Mutex
+	subscriptions map[string]*Subscription
+	videos        map[string]*Video
+}
+
+func New(publicURL string) *Hub {
+	return &Hub{
+		PublicURL:     strings.TrimRight(publicURL, "/"),
+		Client:        &http.Client{Timeout: 10 * time.Second},
+		subscriptions: make(map[string]*Subscription),
+		videos:        make(map[string]*Video),
+	}
+}
+
+func TopicURL(channelID string) string {
+	return topicPrefix + channelID
+}
+
+func (h *Hub) Handler() http.Handler {
+	router := gin.Default()
+
+	router.POST("/subscribe", h.handleSubscribe)
+	router.GET("/subscriptions", h.handleSubscriptions)
+	router.POST("/inject/upload", h.handleInjectUpload)
+	router.POST("/inject/delete", h.handleInjectDelete)
+
+	router.GET("/feeds/videos.xml", h.handleChannelFeed)
+	router.GET("/youtube/v3/videos", h.handleVideosAPI)
+	router.GET("/youtube/v3/channels", h.handleChannelsAPI)
+	router.GET("/shorts/:id", h.handleShortsProbe)
+
+	return router
+}
+
+func (h *Hub) handleSubscribe(c *gin.Context) {
+	mode := c.PostForm("hub.mode")
+	callback := c.PostForm("hub.callback")
+	topic := c.PostForm("hub.topic")
+
+	if mode != "subscribe" && mode != "unsubscribe" {
+		c.String(http.StatusBadRequest, "hub.mode invalide")
+		return
+	}
+	if _, err := url.ParseRequestURI(callback); err != nil {
+		c.String(http.StatusBadRequest, "hub.callback invalide")
+		return
+	}
+	if !strings.HasPrefix(topic, topicPrefix) {
+		c.String(http.StatusBadRequest, "hub.topic invalide")
+		return
+	}
+
+	lease, err := strconv.Atoi(c.PostForm("hub.lease_seconds"))
+	if err != nil || lease <= 0 {
+		lease = defaultLeaseSeconds
+	}
+
+	sub := &Subscription{
+		Topic:        topic,
+		Callback:     callback,
+		Secret:       c.PostForm("hub.secret"),
+		Signed:       c.PostForm("hub.secret") != "",
+		LeaseSeconds: lease,
+	}
+	go h.verify(mode, sub, c.PostForm("hub.verify_token"))
+
+	c.Status(http.StatusAccepted)
+}
+
+// Vérification d'intention : GET sur le callback, qui doit renvoyer le challenge.
+func (h *Hub) verify(mode string, sub *Subscription, verifyToken string) {
+	challenge, err := randomHex(16)
+	if err != nil {
+		log.Printf("localhub: génération du challenge impossible : %v", err)
+		return
+	}
+
+	query := url.Values{}
+	query.Set("hub.mode", mode)
+	query.Set("hub.topic", sub.Topic)
+	query.Set("hub.challenge", challenge)
+	query.Set("hub.lease_seconds", strconv.Itoa(sub.LeaseSeconds))
+	if verifyToken != "" {
+		query.Set("hub.verify_token", verifyToken)
+	}
+
+	verifyURL := sub.Callback
+	if strings.Contains(verifyURL, "?") {
+		verifyURL += "&" + query.Encode()
+	} else {
+		verifyURL += "?" + query.Encode()
+	}
+
+	resp, err := h.Client.Get(verifyURL)
+	if err != nil {
+		log.Printf("localhub: vérification %s échouée pour %s : %v", mode, sub.Callback, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 || string(body) != challenge {
+		log.Printf("localhub: vérification %s refusée par %s (status %d)", mode, sub.Callback, resp.StatusCode)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := sub.Topic + " " + sub.Callback
+	if mode == "unsubscribe" {
+		delete(h.subscriptions, key)
+		log.Printf("localhub: désabonnement de %s pour %s", sub.Callback, sub.Topic)
+		return
+	}
+
+	sub.VerifiedAt = time.Now()
+	sub.ExpiresAt = sub.VerifiedAt.Add(time.Duration(sub.LeaseSeconds) * time.Second)
+	h.subscriptions[key] = sub
+	log.Printf("localhub: abonnement de %s pour %s (%ds)", sub.Callback, sub.Topic, sub.LeaseSeconds)
+}
+
+func (h *Hub) Subscriptions() []Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := make([]Subscription, 0, len(h.subscriptions))
+	for _, sub := range h.subscriptions {
+		subs = append(subs, *sub)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].Topic < subs[j].Topic })
+	return subs
+}
+
+func (h *Hub) handleSubscriptions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Subscriptions())
+}
+
+// Upload enregistre (ou met à jour) une vidéo synthétique et notifie les abonnés de sa chaîne.
+func (h *Hub) Upload(video Video) []Delivery {
+	now := time.Now().UTC()
+
+	h.mu.Lock()
+	if existing, ok := h.videos[video.VideoID]; ok && video.PublishedAt.IsZero() {
+		video.PublishedAt = existing.PublishedAt
+	}
+	if video.PublishedAt.IsZero() {
+		video.PublishedAt = now
+	}
+	if video.Duration == "" && video.Short {
+		video.Duration = "PT30S"
+	} else if video.Duration == "" {
+		video.Duration = "PT5M"
+	}
+	video.UpdatedAt = now
+	video.Deleted = false
+	stored := video
+	h.videos[video.VideoID] = &stored
+	h.mu.Unlock()
+
+	var body bytes.Buffer
+	if err := uploadTemplate.Execute(&body, map[string]interface{}{"Hub": h.PublicURL, "Topic": TopicURL(video.ChannelID), "Video": video}); err != nil {
+		return []Delivery{{Error: err.Error()}}
+	}
+	return h.publish(TopicURL(video.ChannelID), body.Bytes())
+}
+
+// Delete marque une vidéo comme supprimée et envoie un at:deleted-entry aux abonnés.
+func (h *Hub) Delete(channelID string, videoID string) []Delivery {
+	h.mu.Lock()
+	if video, ok := h.videos[videoID]; ok {
+		video.Deleted = true
+		channelID = video.ChannelID
+	}
+	h.mu.Unlock()
+
+	var body bytes.Buffer
+	data := map[string]interface{}{"Hub": h.PublicURL, "Topic": TopicURL(channelID), "ChannelID": channelID, "VideoID": videoID, "When": time.Now().UTC()}
+	if err := deleteTemplate.Execute(&body, data); err != nil {
+		return []Delivery{{Error: err.Error()}}
+	}
+	return h.publish(TopicURL(channelID), body.Bytes())
+}
+
+func (h *Hub) publish(topic string, body []byte) []Delivery {
+	var targets []Subscription
+	for _, sub := range h.Subscriptions() {
+		if sub.Topic == topic && time.Now().Before(sub.ExpiresAt) {
+			targets = append(targets, sub)
+		}
+	}
+
+	deliveries := []Delivery{}
+	for _, sub := range targets {
+		deliveries = append(deliveries, h.push(sub, body))
+	}
+	return deliveries
+}
+
+func (h *Hub) push(sub Subscription, body []byte) Delivery {
+	delivery := Delivery{Callback: sub.Callback}
+
+	req, err := http.NewRequest(http.MethodPost, sub.Callback, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/atom+xml")
+	req.Header.Add("Link", fmt.Sprintf("<%s/subscribe>; rel=hub", h.PublicURL))
+	req.Header.Add("Link", fmt.Sprintf("<%s>; rel=self", sub.Topic))
+	if sub.Secret != "" {
+		mac := hmac.New(sha1.New, []byte(sub.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	return delivery
+}
+
+func (h *Hub) handleInjectUpload(c *gin.Context) {
+	var video Video
+	if err := c.ShouldBindJSON(&video); err != nil || video.VideoID == "" || video.ChannelID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "video_id et channel_id sont requis"})
+		return
+	}
+	if video.Title == "" {
+		video.Title = "Vidéo " + video.VideoID
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": h.Upload(video)})
+}
+
+func (h *Hub) handleInjectDelete(c *gin.Context) {
+	var req struct {
+		VideoID   string `json:"video_id"`
+		ChannelID string `json:"channel_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.VideoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "video_id est requis"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": h.Delete(req.ChannelID, req.VideoID)})
+}
+
+func (h *Hub) channelVideos(channelID string) []Video {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var videos []Video
+	for _, video := range h.videos {
+		if video.ChannelID == channelID && !video.Deleted {
+			videos = append(videos, *video)
+		}
+	}
+	sort.Slice(videos, func(i, j int) bool { return videos[i].PublishedAt.After(videos[j].PublishedAt) })
+	return videos
+}
+
+func (h *Hub) handleChannelFeed(c *gin.Context) {
+	channelID := c.Query("channel_id")
+	videos := h.channelVideos(channelID)
+	if len(videos) > 15 {
+		videos = videos[:15]
+	}
+
+	var body bytes.Buffer
+	data := map[string]interface{}{"ChannelID": channelID, "Videos": videos}
+	if err := channelFeedTemplate.Execute(&body, data); err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/atom+xml", body.Bytes())
+}
+
+// Réponses au format de l'API Data v3 pour les champs lus par le backend.
+func (h *Hub) handleVideosAPI(c *gin.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	items := []gin.H{}
+	for _, id := range strings.Split(c.Query("id"), ",") {
+		video, ok := h.videos[id]
+		if !ok || video.Deleted {
+			continue
+		}
+		items = append(items, gin.H{
+			"id": video.VideoID,
+			"snippet": gin.H{
+				"title":       video.Title,
+				"description": video.Description,
+				"publishedAt": video.PublishedAt.Format(time.RFC3339),
+				"channelId":   video.ChannelID,
+				"categoryId":  "22",
+				"thumbnails":  gin.H{"default": gin.H{"url": "https://i.ytimg.com/vi/" + video.VideoID + "/default.jpg"}},
+			},
+			"contentDetails": gin.H{"duration": video.Duration},
+			"statistics": gin.H{
+				"viewCount":    strconv.FormatInt(video.ViewCount, 10),
+				"likeCount":    strconv.FormatInt(video.LikeCount, 10),
+				"commentCount": strconv.FormatInt(video.CommentCount, 10),
+			},
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+func (h *Hub) handleChannelsAPI(c *gin.Context) {
+	items := []gin.H{}
+	for _, id := range strings.Split(c.Query("id"), ",") {
+		if id == "" {
+			continue
+		}
+		videos := h.channelVideos(id)
+		var views int64
+		for _, video := range videos {
+			views += video.ViewCount
+		}
+		items = append(items, gin.H{
+			"id": id,
+			"snippet": gin.H{
+				"title":       "Chaîne " + id,
+				"publishedAt": "2020-01-01T00:00:00Z",
+				"country":     "FR",
+			},
+			"statistics": gin.H{
+				"subscriberCount": "1000",
+				"viewCount":       strconv.FormatInt(views, 10),
+				"videoCount":      strconv.Itoa(len(videos)),
+			},
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Comme youtube.com : 200 pour un Short, redirection 303 vers /watch sinon.
+func (h *Hub) handleShortsProbe(c *gin.Context) {
+	id := c.Param("id")
+
+	h.mu.Lock()
+	video, ok := h.videos[id]
+	h.mu.Unlock()
+
+	if !ok || video.Deleted {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if video.Short {
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/watch?v="+url.QueryEscape(id))
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+var templateFuncs = template.FuncMap{
+	"xml":     xmlEscape,
+	"rfc3339": func(t time.Time) string { return t.Format(time.RFC3339Nano) },
+}
+
+var uploadTemplate = template.Must(template.New("upload").Funcs(templateFuncs).Parse(`<?xml version='1.0' encoding='UTF-8'?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <link rel="hub" href="{{xml .Hub}}/subscribe"/>
+  <link rel="self" href="{{xml .Topic}}"/>
+  <title>YouTube video feed</title>
+  <updated>{{rfc3339 .Video.UpdatedAt}}</updated>
+  <entry>
+    <id>yt:video:{{xml .Video.VideoID}}</id>
+    <yt:videoId>{{xml .Video.VideoID}}</yt:videoId>
+    <yt:channelId>{{xml .Video.ChannelID}}</yt:channelId>
+    <title>{{xml .Video.Title}}</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v={{xml .Video.VideoID}}"/>
+    <author>
+      <name>Chaîne {{xml .Video.ChannelID}}</name>
+      <uri>https://www.youtube.com/channel/{{xml .Video.ChannelID}}</uri>
+    </author>
+    <published>{{rfc3339 .Video.PublishedAt}}</published>
+    <updated>{{rfc3339 .Video.UpdatedAt}}</updated>
+  </entry>
+</feed>
+`))
+
+var deleteTemplate = template.Must(template.New("delete").Funcs(templateFuncs).Parse(`<?xml version='1.0' encoding='UTF-8'?>
+<feed xmlns:at="http://purl.org/atompub/tombstones/1.0" xmlns="http://www.w3.org/2005/Atom">
+  <link rel="hub" href="{{xml .Hub}}/subscribe"/>
+  <link rel="self" href="{{xml .Topic}}"/>
+  <at:deleted-entry ref="yt:video:{{xml .VideoID}}" when="{{rfc3339 .When}}">
+    <link href="https://www.youtube.com/watch?v={{xml .VideoID}}"/>
+    <at:by>
+      <name>Chaîne {{xml .ChannelID}}</name>
+      <uri>https://www.youtube.com/channel/{{xml .ChannelID}}</uri>
+    </at:by>
+  </at:deleted-entry>
+</feed>
+`))
+
+var channelFeedTemplate = template.Must(template.New("channel").Funcs(templateFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+  <link rel="self" href="https://www.youtube.com/feeds/videos.xml?channel_id={{xml .ChannelID}}"/>
+  <id>yt:channel:{{xml .ChannelID}}</id>
+  <yt:channelId>{{xml .ChannelID}}</yt:channelId>
+  <title>Chaîne {{xml .ChannelID}}</title>
+{{- range .Videos}}
+  <entry>
+    <id>yt:video:{{xml .VideoID}}</id>
+    <yt:videoId>{{xml .VideoID}}</yt:videoId>
+    <yt:channelId>{{xml .ChannelID}}</yt:channelId>
+    <title>{{xml .Title}}</title>
+    <published>{{rfc3339 .PublishedAt}}</published>
+    <updated>{{rfc3339 .UpdatedAt}}</updated>
+    <media:group>
+      <media:title>{{xml .Title}}</media:title>
+      <media:community>
+        <media:starRating count="{{.LikeCount}}" average="5.00" min="1" max="5"/>
+        <media:statistics views="{{.ViewCount}}"/>
+      </media:community>
+    </media:group>
+  </entry>
+{{- end}}
+</feed>
+`))
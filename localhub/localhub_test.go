@@ -0,0 +1,177 @@
+package localhub
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"ytst-back/logic"
+
+	"github.com/gin-gonic/gin"
+)
+
+type notification struct {
+	topic     string
+	signature string
+	body      []byte
+}
+
+// Abonné minimal : répond au challenge si le verify_token correspond et transmet
+// les notifications reçues.
+func subscriberServer(t *testing.T, verifyToken string, notifications chan<- notification) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("hub.verify_token") != verifyToken {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			io.WriteString(w, r.URL.Query().Get("hub.challenge"))
+		case http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("lecture de la notification : %v", err)
+			}
+			notifications <- notification{
+				topic:     logic.NotificationTopic(r.Header.Values("Link"), body),
+				signature: r.Header.Get("X-Hub-Signature"),
+				body:      body,
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func waitForSubscription(t *testing.T, hub *Hub) Subscription {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if subs := hub.Subscriptions(); len(subs) > 0 {
+			return subs[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("abonnement non vérifié")
+	return Subscription{}
+}
+
+func TestSubscribeVerifyAndSignedPush(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := New("http://hub.test")
+	hubServer := httptest.NewServer(hub.Handler())
+	defer hubServer.Close()
+
+	notifications := make(chan notification, 1)
+	callback := subscriberServer(t, "token", notifications)
+	defer callback.Close()
+
+	const secret = "s3cr3t"
+	topic := TopicURL("UCtest")
+	resp, err := http.PostForm(hubServer.URL+"/subscribe", url.Values{
+		"hub.mode":          {"subscribe"},
+		"hub.callback":      {callback.URL},
+		"hub.topic":         {topic},
+		"hub.verify_token":  {"token"},
+		"hub.secret":        {secret},
+		"hub.lease_seconds": {"3600"},
+	})
+	if err != nil {
+		t.Fatalf("abonnement : %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("abonnement : statut %d, attendu 202", resp.StatusCode)
+	}
+
+	sub := waitForSubscription(t, hub)
+	if sub.Topic != topic || !sub.Signed || sub.LeaseSeconds != 3600 {
+		t.Fatalf("abonnement inattendu : %+v", sub)
+	}
+
+	deliveries := hub.Upload(Video{VideoID: "abc123", ChannelID: "UCtest", Title: "Test"})
+	if len(deliveries) != 1 || deliveries[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("livraisons : %+v", deliveries)
+	}
+
+	n := <-notifications
+	if n.topic != topic {
+		t.Errorf("topic = %q, attendu %q", n.topic, topic)
+	}
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(n.body)
+	if want := "sha1=" + hex.EncodeToString(mac.Sum(nil)); n.signature != want {
+		t.Errorf("signature = %q, attendu %q", n.signature, want)
+	}
+	feed, err := logic.ParseHubFeed(n.body)
+	if err != nil {
+		t.Fatalf("notification illisible : %v", err)
+	}
+	if len(feed.Entry) != 1 || feed.Entry[0].VideoId != "abc123" || feed.Entry[0].ChannelId != "UCtest" {
+		t.Errorf("entrées inattendues : %+v", feed.Entry)
+	}
+}
+
+func TestSubscribeRejectedChallenge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := New("http://hub.test")
+	hubServer := httptest.NewServer(hub.Handler())
+	defer hubServer.Close()
+
+	callback := subscriberServer(t, "token", make(chan notification, 1))
+	defer callback.Close()
+
+	resp, err := http.PostForm(hubServer.URL+"/subscribe", url.Values{
+		"hub.mode":         {"subscribe"},
+		"hub.callback":     {callback.URL},
+		"hub.topic":        {TopicURL("UCtest")},
+		"hub.verify_token": {"autre"},
+	})
+	if err != nil {
+		t.Fatalf("abonnement : %v", err)
+	}
+	resp.Body.Close()
+
+	time.Sleep(200 * time.Millisecond)
+	if subs := hub.Subscriptions(); len(subs) != 0 {
+		t.Fatalf("abonnement enregistré malgré un challenge refusé : %+v", subs)
+	}
+}
+
+func TestShortsProbe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := New("http://hub.test")
+	hubServer := httptest.NewServer(hub.Handler())
+	defer hubServer.Close()
+
+	hub.Upload(Video{VideoID: "short1", ChannelID: "UCtest", Short: true})
+	hub.Upload(Video{VideoID: "long1", ChannelID: "UCtest"})
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	tests := []struct {
+		id       string
+		status   int
+		location string
+	}{
+		{"short1", http.StatusOK, ""},
+		{"long1", http.StatusSeeOther, "/watch?v=long1"},
+		{"inconnue", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		resp, err := client.Get(hubServer.URL + "/shorts/" + tt.id)
+		if err != nil {
+			t.Fatalf("%s : %v", tt.id, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || !strings.HasSuffix(resp.Header.Get("Location"), tt.location) {
+			t.Errorf("%s : statut %d (%s), attendu %d (%s)", tt.id, resp.StatusCode, resp.Header.Get("Location"), tt.status, tt.location)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const maxAnalyticsBuckets = 5000
+
+type namedSeries struct {
+	metric string
+	points []analytics.Point
+}
+
+func videoSeries(snapshots []config.VideoSnapshot) (views, likes, comments []analytics.Point) {
+	for _, s := range snapshots {
+		views = append(views, analytics.Point{Time: s.RecordedAt, Value: float64(s.Views)})
+		if s.Likes != nil {
+			likes = append(likes, analytics.Point{Time: s.RecordedAt, Value: float64(*s.Likes)})
+		}
+		if s.Comments != nil {
+			comments = append(comments, analytics.Point{Time: s.RecordedAt, Value: float64(*s.Comments)})
+		}
+	}
+	return analytics.SortPoints(views), analytics.SortPoints(likes), analytics.SortPoints(comments)
+}
+
+func channelSeries(snapshots []config.ChannelSnapshot) (subscribers, views, videos []analytics.Point) {
+	for _, s := range snapshots {
+		subscribers = append(subscribers, analytics.Point{Time: s.RecordedAt, Value: float64(s.Subscribers)})
+		views = append(views, analytics.Point{Time: s.RecordedAt, Value: float64(s.Views)})
+		videos = append(videos, analytics.Point{Time: s.RecordedAt, Value: float64(s.Videos)})
+	}
+	return analytics.SortPoints(subscribers), analytics.SortPoints(views), analytics.SortPoints(videos)
+}
+
+func VideoAnalytics(dbConn *sql.DB, videoId string, from *time.Time, to *time.Time, bucket time.Duration) (config.SeriesAnalytics, error) {
+	snapshots, err := db.VideoSnapshots(dbConn, videoId)
+	if err != nil {
+		return config.SeriesAnalytics{}, err
+	}
+	if len(snapshots) == 0 {
+		return config.SeriesAnalytics{}, fmt.Errorf("Aucune statistique pour la vidéo '%s'", videoId)
+	}
+
+	views, likes, comments := videoSeries(snapshots)
+	return seriesAnalytics(videoId, []namedSeries{{"views", views}, {"likes", likes}, {"comments", comments}}, from, to, bucket)
+}
+
+func ChannelAnalytics(dbConn *sql.DB, channelId string, from *time.Time, to *time.Time, bucket time.Duration) (config.SeriesAnalytics, error) {
+	snapshots, err := db.ChannelSnapshots(dbConn, channelId)
+	if err != nil {
+		return config.SeriesAnalytics{}, err
+	}
+	if len(snapshots) == 0 {
+		return config.SeriesAnalytics{}, fmt.Errorf("Aucune statistique pour la chaîne '%s'", channelId)
+	}
+
+	subscribers, views, videos := channelSeries(snapshots)
+	return seriesAnalytics(channelId, []namedSeries{{"subscribers", subscribers}, {"views", views}, {"videos", videos}}, from, to, bucket)
+}
+
+// Les relevés sont irréguliers : on les rééchantillonne sur une grille de pas bucket
+// avant de calculer écarts, rythmes horaires et accélérations.
+func seriesAnalytics(id string, series []namedSeries, from *time.Time, to *time.Time, bucket time.Duration) (config.SeriesAnalytics, error) {
+	start, end := seriesBounds(series)
+	if from != nil {
+		start = *from
+	}
+	if to != nil {
+		end = *to
+	}
+	if end.Before(start) {
+		return config.SeriesAnalytics{}, fmt.Errorf("La date de fin précède la date de début")
+	}
+	if end.Sub(start)/bucket > maxAnalyticsBuckets {
+		return config.SeriesAnalytics{}, fmt.Errorf("Trop d'intervalles demandés (maximum %d)", maxAnalyticsBuckets)
+	}
+
+	result := config.SeriesAnalytics{ID: id, From: start, To: end, Bucket: bucket.String()}
+	for _, s := range series {
+		result.Metrics = append(result.Metrics, metricAnalytics(s, start, end, bucket))
+	}
+	return result, nil
+}
+
+func seriesBounds(series []namedSeries) (time.Time, time.Time) {
+	var start, end time.Time
+	for _, s := range series {
+		if len(s.points) == 0 {
+			continue
+		}
+		if first := s.points[0].Time; start.IsZero() || first.Before(start) {
+			start = first
+		}
+		if last := s.points[len(s.points)-1].Time; last.After(end) {
+			end = last
+		}
+	}
+	return start, end
+}
+
+func metricAnalytics(s namedSeries, start time.Time, end time.Time, bucket time.Duration) config.MetricAnalytics {
+	metric := config.MetricAnalytics{Metric: s.metric, Intervals: []config.AnalyticsInterval{}}
+
+	var upToEnd []analytics.Point
+	for _, p := range s.points {
+		if !p.Time.After(end) {
+			upToEnd = append(upToEnd, p)
+		}
+	}
+	if len(upToEnd) > 0 {
+		latest := upToEnd[len(upToEnd)-1].Value
+		metric.Latest = &latest
+	}
+	metric.Gain24h = optionalGain(upToEnd, 24*time.Hour)
+	metric.Gain7d = optionalGain(upToEnd, 7*24*time.Hour)
+	metric.Gain30d = optionalGain(upToEnd, 30*24*time.Hour)
+
+	for _, interval := range analytics.Intervals(analytics.Resample(s.points, start, end, bucket)) {
+		metric.Intervals = append(metric.Intervals, config.AnalyticsInterval{
+			Start:        interval.Start,
+			End:          interval.End,
+			Value:        interval.EndValue,
+			Delta:        interval.Delta,
+			PerHour:      interval.PerHour,
+			Acceleration: interval.Acceleration,
+		})
+	}
+	return metric
+}
+
+func optionalGain(points []analytics.Point, window time.Duration) *float64 {
+	gain, ok := analytics.Gain(points, window)
+	if !ok {
+		return nil
+	}
+	return &gain
+}
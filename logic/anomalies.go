@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"database/sql"
+	"log"
+	"time"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const anomalyDetectionInterval = 6 * time.Hour
+
+// Parcourt les séries d'abonnés et de vues des chaînes ainsi que les vues des vidéos.
+func detectAnomalies(dbConn *sql.DB, _ time.Duration) {
+	channels, err := db.TrackedChannels(dbConn)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des chaînes : %v", err)
+		return
+	}
+
+	detected := 0
+	for _, channel := range channels {
+		snapshots, err := db.ChannelSnapshots(dbConn, channel.ChannelID)
+		if err != nil {
+			log.Printf("Erreur lors de la récupération des statistiques de la chaîne '%s' : %v", channel.ChannelID, err)
+			continue
+		}
+		subscribers, views, _ := channelSeries(snapshots)
+		detected += saveAnomalies(dbConn, channel.DBID, nil, "subscribers", analytics.DetectAnomalies(subscribers, analytics.AnomalyOptions{DecreaseIsPurge: true, Rounded: true}))
+		detected += saveAnomalies(dbConn, channel.DBID, nil, "views", analytics.DetectAnomalies(views, analytics.AnomalyOptions{}))
+	}
+
+	// Les compteurs du flux RSS sont en retard sur l'API : mélangés, ils passeraient pour des corrections.
+	histories, err := db.VideoViewHistories(dbConn, "", "api")
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des historiques de vues : %v", err)
+		return
+	}
+	for _, h := range histories {
+		points := make([]analytics.Point, len(h.Views))
+		for i, views := range h.Views {
+			points[i] = analytics.Point{Time: h.RecordedAt[i], Value: float64(views)}
+		}
+		videoDBID := h.VideoDBID
+		detected += saveAnomalies(dbConn, h.ChannelDBID, &videoDBID, "views", analytics.DetectAnomalies(analytics.SortPoints(points), analytics.AnomalyOptions{}))
+	}
+
+	log.Printf("Détection d'anomalies terminée : %d nouvelle(s) anomalie(s)", detected)
+}
+
+func saveAnomalies(dbConn *sql.DB, channelDBID int, videoDBID *int, metric string, anomalies []analytics.Anomaly) int {
+	saved := 0
+	for _, a := range anomalies {
+		inserted, err := db.SaveAnomaly(dbConn, channelDBID, videoDBID, metric, a.Kind, a.Severity, a.Start, a.End, a.Observed, a.Expected, a.Score)
+		if err != nil {
+			log.Printf("Erreur lors de l'enregistrement d'une anomalie : %v", err)
+			continue
+		}
+		if inserted {
+			saved++
+		}
+	}
+	return saved
+}
+
+func Anomalies(dbConn *sql.DB, channelId string, limit int) ([]config.Anomaly, error) {
+	return db.Anomalies(dbConn, channelId, limit)
+}
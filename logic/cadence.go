@@ -0,0 +1,182 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const minCadenceUploads = 3
+
+type uploadGaps struct {
+	median float64
+	p25    float64
+	p75    float64
+	p90    float64
+}
+
+// Écarts entre publications successives, en heures.
+func computeUploadGaps(uploads []time.Time) (uploadGaps, bool) {
+	if len(uploads) < minCadenceUploads {
+		return uploadGaps{}, false
+	}
+	gaps := make([]float64, 0, len(uploads)-1)
+	for i := 1; i < len(uploads); i++ {
+		gaps = append(gaps, uploads[i].Sub(uploads[i-1]).Hours())
+	}
+	return uploadGaps{
+		median: analytics.Median(gaps),
+		p25:    analytics.Percentile(gaps, 0.25),
+		p75:    analytics.Percentile(gaps, 0.75),
+		p90:    analytics.Percentile(gaps, 0.9),
+	}, true
+}
+
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// La heatmap et les semaines sont calculées dans le fuseau demandé ; heatmap[0] correspond au lundi.
+func UploadCadence(dbConn *sql.DB, channelId string, location *time.Location, now time.Time) (config.UploadCadence, error) {
+	rows, err := db.UploadTimes(dbConn, channelId)
+	if err != nil {
+		return config.UploadCadence{}, err
+	}
+	if len(rows) == 0 {
+		return config.UploadCadence{}, fmt.Errorf("Aucune vidéo pour la chaîne '%s'", channelId)
+	}
+
+	uploads := make([]time.Time, len(rows))
+	for i, r := range rows {
+		uploads[i] = r.PublishedAt
+	}
+	cadence := computeUploadCadence(uploads, location, now)
+	cadence.ChannelID = channelId
+	return cadence, nil
+}
+
+// uploads doit être trié et non vide.
+func computeUploadCadence(publishedAt []time.Time, location *time.Location, now time.Time) config.UploadCadence {
+	uploads := make([]time.Time, len(publishedAt))
+	for i, p := range publishedAt {
+		uploads[i] = p.In(location)
+	}
+	now = now.In(location)
+
+	cadence := config.UploadCadence{Timezone: location.String(), Uploads: len(uploads), Weekly: []config.WeeklyUploads{}}
+
+	perWeek := map[time.Time]int{}
+	for _, u := range uploads {
+		perWeek[weekStart(u)]++
+		cadence.Heatmap[(int(u.Weekday())+6)%7][u.Hour()]++
+	}
+	for week := weekStart(uploads[0]); !week.After(now); week = week.AddDate(0, 0, 7) {
+		cadence.Weekly = append(cadence.Weekly, config.WeeklyUploads{WeekStart: week.Format("2006-01-02"), Uploads: perWeek[week]})
+	}
+	cadence.StreakWeeks = streakWeeks(cadence.Weekly)
+
+	last := uploads[len(uploads)-1]
+	cadence.LastUploadAt = &last
+	gaps, silence, unusual := unusualSilence(uploads, now)
+	cadence.SilenceHours = &silence
+
+	if gaps == nil {
+		return cadence
+	}
+	cadence.MedianGapHours = &gaps.median
+	cadence.P90GapHours = &gaps.p90
+	cadence.UnusualSilence = unusual
+
+	next := &config.NextUploadWindow{
+		Earliest:      last.Add(hoursDuration(gaps.p25)),
+		Expected:      last.Add(hoursDuration(gaps.median)),
+		Latest:        last.Add(hoursDuration(gaps.p75)),
+		PreferredSlot: preferredSlot(cadence.Heatmap),
+	}
+	next.Overdue = now.After(next.Latest)
+	cadence.NextUpload = next
+	return cadence
+}
+
+// Série en cours : semaines consécutives avec au moins une vidéo, la semaine courante
+// n'interrompant pas la série tant qu'elle n'est pas terminée.
+func streakWeeks(weekly []config.WeeklyUploads) int {
+	streak := 0
+	for i := len(weekly) - 1; i >= 0; i-- {
+		if weekly[i].Uploads == 0 {
+			if i == len(weekly)-1 {
+				continue
+			}
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+// Silence depuis la dernière vidéo, en heures ; il est inhabituel au-delà du 90e centile des écarts.
+// Les écarts sont nil s'il n'y a pas assez de vidéos pour les estimer.
+func unusualSilence(uploads []time.Time, now time.Time) (*uploadGaps, float64, bool) {
+	silence := now.Sub(uploads[len(uploads)-1]).Hours()
+	gaps, ok := computeUploadGaps(uploads)
+	if !ok {
+		return nil, silence, false
+	}
+	return &gaps, silence, gaps.p90 > 0 && silence > gaps.p90
+}
+
+func hoursDuration(hours float64) time.Duration {
+	return time.Duration(hours * float64(time.Hour))
+}
+
+func preferredSlot(heatmap [7][24]int) *config.UploadSlot {
+	var slot *config.UploadSlot
+	for day := range heatmap {
+		for hour, uploads := range heatmap[day] {
+			if uploads > 0 && (slot == nil || uploads > slot.Uploads) {
+				slot = &config.UploadSlot{Weekday: time.Weekday((day + 1) % 7).String(), Hour: hour, Uploads: uploads}
+			}
+		}
+	}
+	return slot
+}
+
+// Chaînes dont le silence actuel dépasse le 90e centile de leurs écarts habituels.
+func SilentChannels(dbConn *sql.DB, now time.Time) ([]config.SilentChannel, error) {
+	rows, err := db.UploadTimes(dbConn, "")
+	if err != nil {
+		return nil, err
+	}
+
+	silent := []config.SilentChannel{}
+	for start := 0; start < len(rows); {
+		end := start
+		var uploads []time.Time
+		for ; end < len(rows) && rows[end].ChannelID == rows[start].ChannelID; end++ {
+			uploads = append(uploads, rows[end].PublishedAt)
+		}
+
+		if gaps, silence, unusual := unusualSilence(uploads, now); unusual {
+			silent = append(silent, config.SilentChannel{
+				ChannelID:      rows[start].ChannelID,
+				Name:           rows[start].Name,
+				LastUploadAt:   uploads[len(uploads)-1],
+				SilenceHours:   silence,
+				MedianGapHours: gaps.median,
+				P90GapHours:    gaps.p90,
+			})
+		}
+		start = end
+	}
+
+	sort.SliceStable(silent, func(i, j int) bool {
+		return silent[i].SilenceHours/silent[i].P90GapHours > silent[j].SilenceHours/silent[j].P90GapHours
+	})
+	return silent, nil
+}
@@ -0,0 +1,195 @@
+package logic
+
+import (
+	"testing"
+	"time"
+)
+
+func hoursAfter(start time.Time, hours ...float64) []time.Time {
+	times := make([]time.Time, len(hours))
+	for i, h := range hours {
+		times[i] = start.Add(time.Duration(h * float64(time.Hour)))
+	}
+	return times
+}
+
+func TestComputeUploadGaps(t *testing.T) {
+	start := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		uploads []time.Time
+		ok      bool
+		want    uploadGaps
+	}{
+		{"aucune vidéo", nil, false, uploadGaps{}},
+		{"deux vidéos", hoursAfter(start, 0, 24), false, uploadGaps{}},
+		{"écarts réguliers", hoursAfter(start, 0, 24, 48, 72), true, uploadGaps{median: 24, p25: 24, p75: 24, p90: 24}},
+		{"écarts irréguliers", hoursAfter(start, 0, 10, 30, 60, 100), true, uploadGaps{median: 25, p25: 17.5, p75: 32.5, p90: 37}},
+		{"publications simultanées", hoursAfter(start, 0, 0, 24), true, uploadGaps{median: 12, p25: 6, p75: 18, p90: 21.6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := computeUploadGaps(tt.uploads)
+			if ok != tt.ok {
+				t.Fatalf("computeUploadGaps() ok = %v, attendu %v", ok, tt.ok)
+			}
+			if ok && got != tt.want {
+				t.Errorf("computeUploadGaps() = %+v, attendu %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("fuseau Europe/Paris indisponible : %v", err)
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{"lundi", time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"mercredi", time.Date(2026, 3, 4, 23, 59, 0, 0, time.UTC), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"dimanche", time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"changement d'année", time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC), time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{"fuseau local", time.Date(2026, 3, 2, 0, 30, 0, 0, paris), time.Date(2026, 3, 2, 0, 0, 0, 0, paris)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weekStart(tt.t); !got.Equal(tt.want) {
+				t.Errorf("weekStart(%v) = %v, attendu %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeUploadCadence(t *testing.T) {
+	// Lundi 2 mars 2026, 18h UTC.
+	monday := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	weekly := func(weeks ...int) []time.Time {
+		var uploads []time.Time
+		for _, w := range weeks {
+			uploads = append(uploads, monday.AddDate(0, 0, 7*w))
+		}
+		return uploads
+	}
+
+	tests := []struct {
+		name        string
+		uploads     []time.Time
+		now         time.Time
+		streak      int
+		weeks       int
+		unusual     bool
+		overdue     bool
+		nextUpload  bool
+		silenceHour float64
+	}{
+		{
+			name:        "série hebdomadaire, semaine en cours sans vidéo",
+			uploads:     weekly(0, 1, 2, 3),
+			now:         monday.AddDate(0, 0, 7*4).Add(-12 * time.Hour),
+			streak:      4,
+			weeks:       5,
+			nextUpload:  true,
+			silenceHour: 7*24 - 12,
+		},
+		{
+			name:        "vidéo hebdomadaire en retard",
+			uploads:     weekly(0, 1, 2, 3),
+			now:         monday.AddDate(0, 0, 7*4).Add(12 * time.Hour),
+			streak:      4,
+			weeks:       5,
+			unusual:     true,
+			nextUpload:  true,
+			overdue:     true,
+			silenceHour: 7*24 + 12,
+		},
+		{
+			name:        "série interrompue",
+			uploads:     weekly(0, 1, 3),
+			now:         monday.AddDate(0, 0, 7*3).Add(time.Hour),
+			streak:      1,
+			weeks:       4,
+			nextUpload:  true,
+			silenceHour: 1,
+		},
+		{
+			name:        "silence inhabituel",
+			uploads:     weekly(0, 1, 2, 3),
+			now:         monday.AddDate(0, 0, 7*6),
+			streak:      0,
+			weeks:       7,
+			unusual:     true,
+			overdue:     true,
+			nextUpload:  true,
+			silenceHour: 3 * 7 * 24,
+		},
+		{
+			name:        "trop peu de vidéos pour une prévision",
+			uploads:     weekly(0, 1),
+			now:         monday.AddDate(0, 0, 7*2).Add(time.Hour),
+			streak:      2,
+			weeks:       3,
+			silenceHour: 7*24 + 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cadence := computeUploadCadence(tt.uploads, time.UTC, tt.now)
+			if cadence.StreakWeeks != tt.streak {
+				t.Errorf("StreakWeeks = %d, attendu %d", cadence.StreakWeeks, tt.streak)
+			}
+			if len(cadence.Weekly) != tt.weeks {
+				t.Errorf("%d semaines, attendu %d", len(cadence.Weekly), tt.weeks)
+			}
+			if cadence.UnusualSilence != tt.unusual {
+				t.Errorf("UnusualSilence = %v, attendu %v", cadence.UnusualSilence, tt.unusual)
+			}
+			if *cadence.SilenceHours != tt.silenceHour {
+				t.Errorf("SilenceHours = %v, attendu %v", *cadence.SilenceHours, tt.silenceHour)
+			}
+			if (cadence.NextUpload != nil) != tt.nextUpload {
+				t.Fatalf("NextUpload = %+v, attendu présent : %v", cadence.NextUpload, tt.nextUpload)
+			}
+			if cadence.NextUpload != nil && cadence.NextUpload.Overdue != tt.overdue {
+				t.Errorf("Overdue = %v, attendu %v", cadence.NextUpload.Overdue, tt.overdue)
+			}
+		})
+	}
+}
+
+func TestComputeUploadCadenceHeatmap(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("fuseau Europe/Paris indisponible : %v", err)
+	}
+	// Dimanche 23h30 UTC = lundi 0h30 à Paris (heure d'hiver).
+	uploads := []time.Time{
+		time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC),
+		time.Date(2026, 3, 8, 23, 30, 0, 0, time.UTC),
+		time.Date(2026, 3, 11, 17, 0, 0, 0, time.UTC),
+	}
+	cadence := computeUploadCadence(uploads, paris, time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC))
+
+	if cadence.Heatmap[0][0] != 2 {
+		t.Errorf("heatmap[lundi][0h] = %d, attendu 2", cadence.Heatmap[0][0])
+	}
+	if cadence.Heatmap[2][18] != 1 {
+		t.Errorf("heatmap[mercredi][18h] = %d, attendu 1", cadence.Heatmap[2][18])
+	}
+	if cadence.Weekly[0].WeekStart != "2026-03-02" {
+		t.Errorf("première semaine = %s, attendu 2026-03-02", cadence.Weekly[0].WeekStart)
+	}
+	slot := cadence.NextUpload.PreferredSlot
+	if slot == nil || slot.Weekday != "Monday" || slot.Hour != 0 || slot.Uploads != 2 {
+		t.Errorf("créneau préféré = %+v, attendu lundi 0h", slot)
+	}
+}
@@ -0,0 +1,187 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const MaxComparedItems = 10
+
+type comparedItem struct {
+	id     string
+	name   string
+	series []namedSeries
+}
+
+// Les chaînes sont alignées sur une grille calendaire commune, valeurs interpolées entre relevés.
+func CompareChannels(dbConn *sql.DB, channelIds []string, from *time.Time, to *time.Time, bucket time.Duration) (config.Comparison, error) {
+	if err := checkComparedCount(channelIds); err != nil {
+		return config.Comparison{}, err
+	}
+
+	var items []comparedItem
+	var all []namedSeries
+	for _, channelId := range channelIds {
+		channel, err := db.ChannelInfo(dbConn, channelId)
+		if err != nil {
+			return config.Comparison{}, fmt.Errorf("Chaîne '%s' introuvable : %v", channelId, err)
+		}
+		snapshots, err := db.ChannelSnapshots(dbConn, channelId)
+		if err != nil {
+			return config.Comparison{}, err
+		}
+		subscribers, views, videos := channelSeries(snapshots)
+		series := []namedSeries{{"subscribers", subscribers}, {"views", views}, {"videos", videos}}
+		items = append(items, comparedItem{id: channelId, name: channel.Name, series: series})
+		all = append(all, series...)
+	}
+
+	start, end := seriesBounds(all)
+	if from != nil {
+		start = *from
+	}
+	if to != nil {
+		end = *to
+	}
+	grid, err := comparisonGrid(start.Truncate(bucket), end, bucket)
+	if err != nil {
+		return config.Comparison{}, err
+	}
+
+	comparison := config.Comparison{Kind: "channels", Bucket: bucket.String(), Times: grid}
+	comparison.Items = compareItems(items, grid)
+	return comparison, nil
+}
+
+// Les vidéos sont alignées sur leur âge depuis published_at.
+func CompareVideos(dbConn *sql.DB, videoIds []string, bucket time.Duration) (config.Comparison, error) {
+	if err := checkComparedCount(videoIds); err != nil {
+		return config.Comparison{}, err
+	}
+
+	// Toutes les séries sont ramenées sur une origine commune : l'instant zéro correspond à la publication.
+	origin := time.Unix(0, 0).UTC()
+	var items []comparedItem
+	var maxAge time.Duration
+	for _, videoId := range videoIds {
+		video, err := db.VideoInfo(dbConn, videoId)
+		if err != nil {
+			return config.Comparison{}, fmt.Errorf("Vidéo '%s' introuvable : %v", videoId, err)
+		}
+		publishedAt, err := db.VideoPublishedAt(dbConn, videoId)
+		if err != nil {
+			return config.Comparison{}, err
+		}
+		snapshots, err := db.VideoSnapshots(dbConn, videoId)
+		if err != nil {
+			return config.Comparison{}, err
+		}
+
+		views, likes, comments := videoSeries(snapshots)
+		series := []namedSeries{{"views", views}, {"likes", likes}, {"comments", comments}}
+		for i := range series {
+			series[i].points = shiftPoints(series[i].points, origin.Sub(publishedAt))
+			if n := len(series[i].points); n > 0 && series[i].points[n-1].Time.Sub(origin) > maxAge {
+				maxAge = series[i].points[n-1].Time.Sub(origin)
+			}
+		}
+		items = append(items, comparedItem{id: videoId, name: video.Title, series: series})
+	}
+
+	grid, err := comparisonGrid(origin, origin.Add(maxAge), bucket)
+	if err != nil {
+		return config.Comparison{}, err
+	}
+
+	comparison := config.Comparison{Kind: "videos", Bucket: bucket.String(), AgesHours: []float64{}}
+	for _, t := range grid {
+		comparison.AgesHours = append(comparison.AgesHours, t.Sub(origin).Hours())
+	}
+	comparison.Items = compareItems(items, grid)
+	return comparison, nil
+}
+
+func checkComparedCount(ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("Aucun identifiant à comparer")
+	}
+	if len(ids) > MaxComparedItems {
+		return fmt.Errorf("Au plus %d éléments peuvent être comparés", MaxComparedItems)
+	}
+	return nil
+}
+
+func shiftPoints(points []analytics.Point, offset time.Duration) []analytics.Point {
+	shifted := make([]analytics.Point, len(points))
+	for i, p := range points {
+		shifted[i] = analytics.Point{Time: p.Time.Add(offset), Value: p.Value}
+	}
+	return shifted
+}
+
+func comparisonGrid(start time.Time, end time.Time, bucket time.Duration) ([]time.Time, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("La date de fin précède la date de début")
+	}
+	if end.Sub(start)/bucket > maxAnalyticsBuckets {
+		return nil, fmt.Errorf("Trop d'intervalles demandés (maximum %d)", maxAnalyticsBuckets)
+	}
+	var grid []time.Time
+	for t := start; !t.After(end); t = t.Add(bucket) {
+		grid = append(grid, t)
+	}
+	return grid, nil
+}
+
+// Chaque métrique est résumée par sa dernière valeur et sa progression sur la grille ;
+// le rang (1 = plus forte progression) est calculé entre les éléments comparés.
+func compareItems(items []comparedItem, grid []time.Time) []config.ComparisonItem {
+	result := make([]config.ComparisonItem, len(items))
+	for i, item := range items {
+		result[i] = config.ComparisonItem{ID: item.id, Name: item.name}
+		for _, s := range item.series {
+			metric := config.ComparisonMetric{Metric: s.metric, Values: make([]*float64, len(grid))}
+			var first *float64
+			for j, t := range grid {
+				if value, ok := analytics.ValueAt(s.points, t); ok {
+					v := value
+					metric.Values[j] = &v
+					if first == nil {
+						first = &v
+					}
+					metric.Latest = &v
+				}
+			}
+			if first != nil {
+				delta := *metric.Latest - *first
+				metric.Delta = &delta
+			}
+			result[i].Metrics = append(result[i].Metrics, metric)
+		}
+	}
+
+	if len(result) == 0 {
+		return result
+	}
+	for m := range result[0].Metrics {
+		var ranked []int
+		for i := range result {
+			if result[i].Metrics[m].Delta != nil {
+				ranked = append(ranked, i)
+			}
+		}
+		sort.SliceStable(ranked, func(a, b int) bool {
+			return *result[ranked[a]].Metrics[m].Delta > *result[ranked[b]].Metrics[m].Delta
+		})
+		for rank, i := range ranked {
+			r := rank + 1
+			result[i].Metrics[m].Rank = &r
+		}
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const (
+	FormatAll   = "all"
+	FormatShort = "short"
+	FormatLong  = "long"
+
+	minCurveVideos = 3
+)
+
+var curveAges = []time.Duration{
+	1 * time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 18 * time.Hour,
+	24 * time.Hour, 36 * time.Hour, 48 * time.Hour, 72 * time.Hour, 96 * time.Hour,
+	5 * 24 * time.Hour, 7 * 24 * time.Hour, 10 * 24 * time.Hour, 14 * 24 * time.Hour, 21 * 24 * time.Hour,
+	30 * 24 * time.Hour, 45 * 24 * time.Hour, 60 * 24 * time.Hour, 90 * 24 * time.Hour,
+}
+
+func videoFormat(isShort bool) string {
+	if isShort {
+		return FormatShort
+	}
+	return FormatLong
+}
+
+// Trajectoire type d'une chaîne : centiles des vues de ses vidéos alignées sur l'âge
+// depuis published_at, Shorts et vidéos longues séparés. videoId, facultatif, est superposé
+// à la courbe de son format.
+func ChannelCurve(dbConn *sql.DB, channelId string, videoId string) (config.ChannelCurve, error) {
+	histories, err := db.VideoViewHistories(dbConn, channelId, "")
+	if err != nil {
+		return config.ChannelCurve{}, err
+	}
+
+	seriesByFormat := map[string][]videoTrajectory{}
+	var overlay *videoTrajectory
+	for _, h := range histories {
+		trajectory := videoTrajectory{history: h, series: viewSeries(h)}
+		format := videoFormat(h.IsShort)
+		seriesByFormat[format] = append(seriesByFormat[format], trajectory)
+		if h.VideoID == videoId {
+			overlay = &trajectory
+		}
+	}
+	if videoId != "" && overlay == nil {
+		return config.ChannelCurve{}, fmt.Errorf("Aucune statistique pour la vidéo '%s' sur cette chaîne", videoId)
+	}
+
+	result := config.ChannelCurve{ChannelID: channelId, Curves: []config.PerformanceCurve{}}
+	curves := map[string]config.PerformanceCurve{}
+	for _, format := range []string{FormatLong, FormatShort} {
+		curve := performanceCurve(format, seriesByFormat[format])
+		curves[format] = curve
+		result.Curves = append(result.Curves, curve)
+	}
+
+	if overlay != nil {
+		result.Video = curveOverlay(*overlay, curves[videoFormat(overlay.history.IsShort)])
+	}
+	return result, nil
+}
+
+type videoTrajectory struct {
+	history db.VideoViewHistory
+	series  []analytics.Point
+}
+
+func performanceCurve(format string, trajectories []videoTrajectory) config.PerformanceCurve {
+	curve := config.PerformanceCurve{Format: format, Videos: len(trajectories), Points: []config.CurvePoint{}}
+	for _, age := range curveAges {
+		var values []float64
+		for _, t := range trajectories {
+			if views, ok := viewsAtAge(t.series, t.history.PublishedAt, age); ok {
+				values = append(values, views)
+			}
+		}
+		if len(values) < minCurveVideos {
+			continue
+		}
+		curve.Points = append(curve.Points, config.CurvePoint{
+			AgeHours: age.Hours(),
+			P10:      analytics.Percentile(values, 0.1),
+			P50:      analytics.Median(values),
+			P90:      analytics.Percentile(values, 0.9),
+			Videos:   len(values),
+		})
+	}
+	return curve
+}
+
+func curveOverlay(t videoTrajectory, curve config.PerformanceCurve) *config.CurveOverlay {
+	medians := map[float64]float64{}
+	for _, p := range curve.Points {
+		medians[p.AgeHours] = p.P50
+	}
+
+	overlay := &config.CurveOverlay{VideoID: t.history.VideoID, Format: curve.Format, Points: []config.CurveOverlayPoint{}}
+	for _, age := range curveAges {
+		views, ok := viewsAtAge(t.series, t.history.PublishedAt, age)
+		if !ok {
+			continue
+		}
+		point := config.CurveOverlayPoint{AgeHours: age.Hours(), Views: views}
+		if median, ok := medians[age.Hours()]; ok && median > 0 {
+			ratio := views / median
+			point.VsMedian = &ratio
+		}
+		overlay.Points = append(overlay.Points, point)
+	}
+	return overlay
+}
@@ -0,0 +1,106 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const dailyAverageDays = 30
+
+var dailyProjectionDays = []int{30, 90, 180, 365}
+
+// Appelée au démarrage, après l'ajout d'une chaîne et après la mise à jour quotidienne des statistiques.
+func refreshChannelDailyStats(dbConn *sql.DB) {
+	if err := db.RefreshChannelDailyStats(dbConn); err != nil {
+		log.Printf("Erreur lors du rafraîchissement de la vue channel_daily_stats : %v", err)
+	}
+}
+
+// Tableau journalier d'une chaîne sur les days derniers jours (tout l'historique si days vaut 0).
+// Les jours sans relevé sont interpolés entre les jours voisins et marqués comme estimés.
+func ChannelDailySummary(dbConn *sql.DB, channelId string, days int) (config.ChannelDailySummary, error) {
+	stats, err := db.ChannelDailyStats(dbConn, channelId)
+	if err != nil {
+		return config.ChannelDailySummary{}, err
+	}
+	if len(stats) == 0 {
+		return config.ChannelDailySummary{}, fmt.Errorf("Aucune statistique pour la chaîne '%s'", channelId)
+	}
+	uploads, err := db.DailyUploads(dbConn, channelId)
+	if err != nil {
+		return config.ChannelDailySummary{}, err
+	}
+
+	var subscribers, views []analytics.Point
+	known := map[string]bool{}
+	for _, s := range stats {
+		subscribers = append(subscribers, analytics.Point{Time: s.Day, Value: float64(s.Subscribers)})
+		views = append(views, analytics.Point{Time: s.Day, Value: float64(s.Views)})
+		known[s.Day.Format("2006-01-02")] = true
+	}
+
+	var rows []config.DailySummaryRow
+	last := stats[len(stats)-1].Day
+	for day := stats[0].Day; !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		subscriberCount, _ := analytics.ValueAt(subscribers, day)
+		viewCount, _ := analytics.ValueAt(views, day)
+		row := config.DailySummaryRow{
+			Date:        date,
+			Subscribers: int64(math.Round(subscriberCount)),
+			Views:       int64(math.Round(viewCount)),
+			Uploads:     uploads[date],
+			Estimated:   !known[date],
+		}
+		if n := len(rows); n > 0 {
+			subscribersChange := row.Subscribers - rows[n-1].Subscribers
+			viewsChange := row.Views - rows[n-1].Views
+			row.SubscribersChange = &subscribersChange
+			row.ViewsChange = &viewsChange
+		}
+		rows = append(rows, row)
+	}
+
+	summary := config.ChannelDailySummary{ChannelID: channelId, Days: rows, Projections: []config.DailyProjection{}}
+	if days > 0 && len(rows) > days {
+		summary.Days = rows[len(rows)-days:]
+	}
+
+	recent := rows
+	if len(recent) > dailyAverageDays {
+		recent = recent[len(recent)-dailyAverageDays:]
+	}
+	average := config.DailyAverage{}
+	for _, row := range recent {
+		if row.SubscribersChange == nil {
+			continue
+		}
+		average.Days++
+		average.SubscribersChange += float64(*row.SubscribersChange)
+		average.ViewsChange += float64(*row.ViewsChange)
+		average.Uploads += float64(row.Uploads)
+	}
+	if average.Days == 0 {
+		return summary, nil
+	}
+	average.SubscribersChange /= float64(average.Days)
+	average.ViewsChange /= float64(average.Days)
+	average.Uploads /= float64(average.Days)
+	summary.Average30d = &average
+
+	latest := rows[len(rows)-1]
+	for _, d := range dailyProjectionDays {
+		summary.Projections = append(summary.Projections, config.DailyProjection{
+			Days:        d,
+			Date:        last.AddDate(0, 0, d).Format("2006-01-02"),
+			Subscribers: latest.Subscribers + int64(math.Round(average.SubscribersChange*float64(d))),
+			Views:       latest.Views + int64(math.Round(average.ViewsChange*float64(d))),
+		})
+	}
+	return summary, nil
+}
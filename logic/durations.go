@@ -0,0 +1,78 @@
+package logic
+
+import (
+	"database/sql"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+var durationBuckets = []struct {
+	label      string
+	minSeconds int
+	maxSeconds int // exclusif, 0 = sans limite
+}{
+	{"< 1 min", 0, 60},
+	{"1-3 min", 60, 180},
+	{"3-10 min", 180, 600},
+	{"10-20 min", 600, 1200},
+	{"20-60 min", 1200, 3600},
+	{">= 1 h", 3600, 0},
+}
+
+func ChannelDurationStats(dbConn *sql.DB, channelID string) ([]config.ChannelDurationStats, error) {
+	durations, err := db.VideoDurations(dbConn, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Les durées arrivent triées par chaîne puis par durée.
+	stats := []config.ChannelDurationStats{}
+	var seconds []int
+	flush := func() {
+		if len(seconds) == 0 {
+			return
+		}
+		current := &stats[len(stats)-1]
+		current.VideoCount = len(seconds)
+		current.AverageSeconds = float64(current.TotalSeconds) / float64(len(seconds))
+		if n := len(seconds); n%2 == 1 {
+			current.MedianSeconds = float64(seconds[n/2])
+		} else {
+			current.MedianSeconds = float64(seconds[n/2-1]+seconds[n/2]) / 2
+		}
+		seconds = nil
+	}
+
+	for _, d := range durations {
+		if len(stats) == 0 || stats[len(stats)-1].ChannelID != d.ChannelID {
+			flush()
+			stats = append(stats, newChannelDurationStats(d.ChannelID, d.Name))
+		}
+
+		current := &stats[len(stats)-1]
+		current.TotalSeconds += int64(d.Seconds)
+		for i, bucket := range durationBuckets {
+			if d.Seconds >= bucket.minSeconds && (bucket.maxSeconds == 0 || d.Seconds < bucket.maxSeconds) {
+				current.Buckets[i].Count++
+				break
+			}
+		}
+		seconds = append(seconds, d.Seconds)
+	}
+	flush()
+
+	return stats, nil
+}
+
+func newChannelDurationStats(channelID string, name string) config.ChannelDurationStats {
+	stats := config.ChannelDurationStats{ChannelID: channelID, Name: name}
+	for _, bucket := range durationBuckets {
+		b := config.DurationBucket{Label: bucket.label, MinSeconds: bucket.minSeconds}
+		if bucket.maxSeconds > 0 {
+			max := bucket.maxSeconds
+			b.MaxSeconds = &max
+		}
+		stats.Buckets = append(stats.Buckets, b)
+	}
+	return stats
+}
@@ -0,0 +1,168 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const SourceAPI = "api"
+
+func engagementSnapshot(s config.VideoSnapshot) config.EngagementSnapshot {
+	snapshot := config.EngagementSnapshot{RecordedAt: s.RecordedAt, Views: s.Views, Likes: s.Likes, Comments: s.Comments}
+	snapshot.LikesPer1k, snapshot.CommentsPer1k, snapshot.LikeCommentRatio = engagementRates(s.Views, s.Likes, s.Comments)
+	return snapshot
+}
+
+func engagementRates(views int64, likes *int64, comments *int64) (likesPer1k *float64, commentsPer1k *float64, likeCommentRatio *float64) {
+	if views > 0 && likes != nil {
+		v := float64(*likes) * 1000 / float64(views)
+		likesPer1k = &v
+	}
+	if views > 0 && comments != nil {
+		v := float64(*comments) * 1000 / float64(views)
+		commentsPer1k = &v
+	}
+	if likes != nil && comments != nil && *comments > 0 {
+		v := float64(*likes) / float64(*comments)
+		likeCommentRatio = &v
+	}
+	return likesPer1k, commentsPer1k, likeCommentRatio
+}
+
+// Tendances exprimées en variation par jour du taux pour 1 000 vues.
+func VideoEngagement(dbConn *sql.DB, videoId string) (config.VideoEngagement, error) {
+	snapshots, err := db.VideoSnapshots(dbConn, videoId)
+	if err != nil {
+		return config.VideoEngagement{}, err
+	}
+
+	result := config.VideoEngagement{VideoID: videoId, Snapshots: []config.EngagementSnapshot{}}
+	var likeRates, commentRates []analytics.Point
+	for _, s := range snapshots {
+		if s.Source != SourceAPI {
+			continue
+		}
+		snapshot := engagementSnapshot(s)
+		result.Snapshots = append(result.Snapshots, snapshot)
+		if snapshot.LikesPer1k != nil {
+			likeRates = append(likeRates, analytics.Point{Time: s.RecordedAt, Value: *snapshot.LikesPer1k})
+		}
+		if snapshot.CommentsPer1k != nil {
+			commentRates = append(commentRates, analytics.Point{Time: s.RecordedAt, Value: *snapshot.CommentsPer1k})
+		}
+	}
+	if len(result.Snapshots) == 0 {
+		return result, fmt.Errorf("Aucune statistique API pour la vidéo '%s'", videoId)
+	}
+
+	result.Latest = &result.Snapshots[len(result.Snapshots)-1]
+	result.LikesPer1kTrend = optionalSlope(likeRates)
+	result.CommentsPer1kTrend = optionalSlope(commentRates)
+	return result, nil
+}
+
+func optionalSlope(points []analytics.Point) *float64 {
+	slope, ok := analytics.Slope(analytics.SortPoints(points))
+	if !ok {
+		return nil
+	}
+	return &slope
+}
+
+func ChannelEngagement(dbConn *sql.DB, channelId string, lastN int) (config.ChannelEngagement, error) {
+	channels, err := channelEngagements(dbConn, channelId, lastN)
+	if err != nil {
+		return config.ChannelEngagement{}, err
+	}
+	if len(channels) == 0 {
+		return config.ChannelEngagement{}, fmt.Errorf("Aucune statistique API pour la chaîne '%s'", channelId)
+	}
+	return channels[0], nil
+}
+
+// Classement des chaînes sur la médiane des likes pour 1 000 vues du format demandé.
+func EngagementRankings(dbConn *sql.DB, format string, lastN int) ([]config.EngagementRanking, error) {
+	channels, err := channelEngagements(dbConn, "", lastN)
+	if err != nil {
+		return nil, err
+	}
+
+	rankings := []config.EngagementRanking{}
+	for _, channel := range channels {
+		for _, summary := range channel.Formats {
+			if summary.Format == format && summary.MedianLikesPer1k != nil {
+				rankings = append(rankings, config.EngagementRanking{ChannelID: channel.ChannelID, Name: channel.Name, Summary: summary})
+			}
+		}
+	}
+	sort.SliceStable(rankings, func(i, j int) bool {
+		return *rankings[i].Summary.MedianLikesPer1k > *rankings[j].Summary.MedianLikesPer1k
+	})
+	for i := range rankings {
+		rankings[i].Rank = i + 1
+	}
+	return rankings, nil
+}
+
+func channelEngagements(dbConn *sql.DB, channelId string, lastN int) ([]config.ChannelEngagement, error) {
+	rows, err := db.LatestVideoEngagement(dbConn, channelId, lastN)
+	if err != nil {
+		return nil, err
+	}
+
+	var channels []config.ChannelEngagement
+	groups := map[string][]db.VideoEngagementRow{}
+	for _, r := range rows {
+		if n := len(channels); n == 0 || channels[n-1].ChannelID != r.ChannelID {
+			channels = append(channels, config.ChannelEngagement{ChannelID: r.ChannelID, Name: r.Name})
+		}
+		if r.RankAll <= lastN {
+			groups[r.ChannelID+"/"+FormatAll] = append(groups[r.ChannelID+"/"+FormatAll], r)
+		}
+		if r.RankFormat <= lastN {
+			format := videoFormat(r.IsShort)
+			groups[r.ChannelID+"/"+format] = append(groups[r.ChannelID+"/"+format], r)
+		}
+	}
+
+	for i := range channels {
+		channels[i].Uploads = len(groups[channels[i].ChannelID+"/"+FormatAll])
+		for _, format := range []string{FormatAll, FormatLong, FormatShort} {
+			channels[i].Formats = append(channels[i].Formats, engagementSummary(format, groups[channels[i].ChannelID+"/"+format]))
+		}
+	}
+	return channels, nil
+}
+
+func engagementSummary(format string, rows []db.VideoEngagementRow) config.EngagementSummary {
+	summary := config.EngagementSummary{Format: format, Videos: len(rows)}
+	var likes, comments, ratios []float64
+	for _, r := range rows {
+		likesPer1k, commentsPer1k, ratio := engagementRates(r.Views, r.Likes, r.Comments)
+		if likesPer1k != nil {
+			likes = append(likes, *likesPer1k)
+		}
+		if commentsPer1k != nil {
+			comments = append(comments, *commentsPer1k)
+		}
+		if ratio != nil {
+			ratios = append(ratios, *ratio)
+		}
+	}
+	summary.MedianLikesPer1k = optionalMedian(likes)
+	summary.MedianCommentsPer1k = optionalMedian(comments)
+	summary.MedianLikeCommentRatio = optionalMedian(ratios)
+	return summary
+}
+
+func optionalMedian(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	median := analytics.Median(values)
+	return &median
+}
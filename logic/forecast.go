@@ -0,0 +1,133 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const (
+	forecastProjections = 30
+	forecastMaxGrid     = 500
+	// Au-delà, une date d'atteinte n'a plus de sens.
+	milestoneSearchLimit = 10 * 365 * 24 * time.Hour
+)
+
+var (
+	DefaultSubscriberMilestones = []float64{100000, 1000000}
+	DefaultViewMilestones       = []float64{1000000}
+)
+
+// Les jalons portent sur la métrique principale : abonnés pour une chaîne.
+func ChannelForecast(dbConn *sql.DB, channelId string, horizon time.Duration, milestones []float64) (config.Forecast, error) {
+	snapshots, err := db.ChannelSnapshots(dbConn, channelId)
+	if err != nil {
+		return config.Forecast{}, err
+	}
+	if len(snapshots) == 0 {
+		return config.Forecast{}, fmt.Errorf("Aucune statistique pour la chaîne '%s'", channelId)
+	}
+	if milestones == nil {
+		milestones = DefaultSubscriberMilestones
+	}
+
+	subscribers, views, _ := channelSeries(snapshots)
+	forecast := config.Forecast{ID: channelId, Horizon: horizon.String()}
+	for _, s := range []struct {
+		metric     string
+		points     []analytics.Point
+		milestones []float64
+	}{
+		{"subscribers", subscribers, milestones},
+		{"views", views, nil},
+	} {
+		metric, err := metricForecast(s.metric, s.points, horizon, s.milestones)
+		if err != nil {
+			return config.Forecast{}, err
+		}
+		forecast.Metrics = append(forecast.Metrics, metric)
+	}
+	return forecast, nil
+}
+
+func VideoForecast(dbConn *sql.DB, videoId string, horizon time.Duration, milestones []float64) (config.Forecast, error) {
+	snapshots, err := db.VideoSnapshots(dbConn, videoId)
+	if err != nil {
+		return config.Forecast{}, err
+	}
+	if len(snapshots) == 0 {
+		return config.Forecast{}, fmt.Errorf("Aucune statistique pour la vidéo '%s'", videoId)
+	}
+	if milestones == nil {
+		milestones = DefaultViewMilestones
+	}
+
+	views, _, _ := videoSeries(snapshots)
+	metric, err := metricForecast("views", views, horizon, milestones)
+	if err != nil {
+		return config.Forecast{}, err
+	}
+	return config.Forecast{ID: videoId, Horizon: horizon.String(), Metrics: []config.MetricForecast{metric}}, nil
+}
+
+// La série est d'abord rééchantillonnée pour que les périodes très relevées
+// ne pèsent pas plus que les autres dans l'ajustement.
+func metricForecast(metric string, points []analytics.Point, horizon time.Duration, milestones []float64) (config.MetricForecast, error) {
+	fits := analytics.FitModels(forecastGrid(points))
+	if len(fits) == 0 {
+		return config.MetricForecast{}, fmt.Errorf("Pas assez de relevés pour prévoir '%s'", metric)
+	}
+
+	best := fits[0]
+	last := points[len(points)-1]
+	result := config.MetricForecast{
+		Metric:      metric,
+		Model:       best.Model,
+		R2:          best.R2,
+		AdjustedR2:  best.AdjR2,
+		Latest:      last.Value,
+		Projections: []config.ForecastPoint{},
+		Milestones:  []config.MilestoneETA{},
+	}
+	for _, fit := range fits {
+		result.Models = append(result.Models, config.ForecastModelScore{Model: fit.Model, R2: fit.R2, AdjustedR2: fit.AdjR2})
+	}
+
+	step := horizon / forecastProjections
+	for i := 1; i <= forecastProjections; i++ {
+		t := last.Time.Add(time.Duration(i) * step)
+		value, lower, upper := best.Band(t)
+		result.Projections = append(result.Projections, config.ForecastPoint{Time: t, Value: value, Lower: lower, Upper: upper})
+	}
+
+	for _, target := range milestones {
+		milestone := config.MilestoneETA{Target: target}
+		if last.Value >= target {
+			milestone.Reached = true
+		} else {
+			milestone.ETA, milestone.Earliest, milestone.Latest = best.Crossing(target, last.Time, last.Time.Add(milestoneSearchLimit), 24*time.Hour)
+		}
+		result.Milestones = append(result.Milestones, milestone)
+	}
+	return result, nil
+}
+
+func forecastGrid(points []analytics.Point) []analytics.Point {
+	if len(points) < 2 {
+		return points
+	}
+	first, last := points[0].Time, points[len(points)-1].Time
+	step := last.Sub(first) / forecastMaxGrid
+	if step < time.Hour {
+		step = time.Hour
+	}
+
+	grid := analytics.Resample(points, first, last, step)
+	if len(grid) < 3 {
+		return points
+	}
+	return grid
+}
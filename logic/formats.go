@@ -0,0 +1,106 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const MaxBreakdownMonths = 120
+
+// Compare Shorts et vidéos longues d'une chaîne : volume, part des vues, vues médianes
+// à 24h et 7 jours, engagement, et publications mensuelles des months derniers mois.
+func ChannelFormatBreakdown(dbConn *sql.DB, channelId string, months int, now time.Time) (config.ChannelFormatBreakdown, error) {
+	monthly, err := db.MonthlyUploadsByFormat(dbConn, channelId)
+	if err != nil {
+		return config.ChannelFormatBreakdown{}, err
+	}
+	if len(monthly) == 0 {
+		return config.ChannelFormatBreakdown{}, fmt.Errorf("Aucune vidéo pour la chaîne '%s'", channelId)
+	}
+	histories, err := db.VideoViewHistories(dbConn, channelId, "")
+	if err != nil {
+		return config.ChannelFormatBreakdown{}, err
+	}
+	engagement, err := db.LatestVideoEngagement(dbConn, channelId, math.MaxInt32)
+	if err != nil {
+		return config.ChannelFormatBreakdown{}, err
+	}
+
+	breakdowns := map[string]*config.FormatBreakdown{
+		FormatLong:  {Format: FormatLong},
+		FormatShort: {Format: FormatShort},
+	}
+	for _, m := range monthly {
+		breakdowns[videoFormat(m.IsShort)].Videos += m.Uploads
+	}
+
+	at24h := map[string][]float64{}
+	at7d := map[string][]float64{}
+	var totalViews int64
+	for _, h := range histories {
+		format := videoFormat(h.IsShort)
+		latest := h.Views[len(h.Views)-1]
+		breakdowns[format].TotalViews += latest
+		totalViews += latest
+
+		series := viewSeries(h)
+		if views, ok := viewsAtAge(series, h.PublishedAt, 24*time.Hour); ok {
+			at24h[format] = append(at24h[format], views)
+		}
+		if views, ok := viewsAtAge(series, h.PublishedAt, 7*24*time.Hour); ok {
+			at7d[format] = append(at7d[format], views)
+		}
+	}
+
+	engagementRows := map[string][]db.VideoEngagementRow{}
+	for _, r := range engagement {
+		format := videoFormat(r.IsShort)
+		engagementRows[format] = append(engagementRows[format], r)
+	}
+
+	result := config.ChannelFormatBreakdown{ChannelID: channelId, Monthly: []config.MonthlyFormatUploads{}}
+	for _, format := range []string{FormatLong, FormatShort} {
+		b := breakdowns[format]
+		if totalViews > 0 {
+			share := float64(b.TotalViews) / float64(totalViews)
+			b.ViewShare = &share
+		}
+		b.MedianViews24h = optionalMedian(at24h[format])
+		b.MedianViews7d = optionalMedian(at7d[format])
+		b.Engagement = engagementSummary(format, engagementRows[format])
+		result.Formats = append(result.Formats, *b)
+	}
+
+	perMonth := map[string]*config.MonthlyFormatUploads{}
+	for _, m := range monthly {
+		key := m.Month.Format("2006-01")
+		if perMonth[key] == nil {
+			perMonth[key] = &config.MonthlyFormatUploads{Month: key}
+		}
+		if m.IsShort {
+			perMonth[key].Shorts += m.Uploads
+		} else {
+			perMonth[key].Long += m.Uploads
+		}
+	}
+
+	// Tous les mois de la période apparaissent, y compris ceux sans publication.
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for month := current.AddDate(0, -(months - 1), 0); !month.After(current); month = month.AddDate(0, 1, 0) {
+		key := month.Format("2006-01")
+		row := config.MonthlyFormatUploads{Month: key}
+		if perMonth[key] != nil {
+			row = *perMonth[key]
+		}
+		if total := row.Shorts + row.Long; total > 0 {
+			share := float64(row.Shorts) / float64(total)
+			row.ShortShare = &share
+		}
+		result.Monthly = append(result.Monthly, row)
+	}
+	return result, nil
+}
@@ -5,19 +5,35 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"regexp"
-	"strconv"
 	"time"
 	"ytst-back/config"
 	"ytst-back/db"
 	"ytst-back/youtube"
 )
 
-func PeriodicallyCalledRoutes(db *sql.DB) {
+const (
+	DiscoveredViaPush = "push"
+	DiscoveredViaPoll = "poll"
+)
+
+func Configure(cfg *config.Config) {
+	configureHub(cfg)
+	configureShorts(cfg)
+	configureMilestones(cfg)
+}
+
+func PeriodicallyCalledRoutes(db *sql.DB, cfg *config.Config) {
 	fmt.Println("Appels périodiques des routes...")
+	go RenewHubSubscriptions(db, 0)
+	go computeOutlierScores(db, 0)
+	go refreshChannelDailyStats(db)
+	callRoutePeriodically(RenewHubSubscriptions, hubRenewalInterval, db)
 	callRoutePeriodically(updateAllChannelStats, 24*time.Hour, db)
-	//callRoutePeriodically(autoCheckNewVideos, 2*time.Hour, db)
+	callRoutePeriodically(pollChannelFeeds, cfg.RSSPollInterval, db)
+	callRoutePeriodically(pollLiveVideos, cfg.LivePollInterval, db)
 	callRoutePeriodically(refreshWithFrequency, 2*time.Hour, db)
+	callRoutePeriodically(computeOutlierScores, outlierScoreInterval, db)
+	callRoutePeriodically(detectAnomalies, anomalyDetectionInterval, db)
 }
 
 func mapToStruct(data interface{}, result interface{}) error {
@@ -122,8 +138,9 @@ func AddChannel(db *sql.DB, channelId string) error {
 		return fmt.Errorf("Erreur lors de l'insertion en base de données : %v", err)
 	}
 
-	fmt.Printf("Chaîne ajoutée avec succès pour channel_id '%s' avec l'ID '%s'.\n", channelId)
+	fmt.Printf("Chaîne ajoutée avec succès pour channel_id '%s'.\n", channelId)
 	refreshChannelStats(db, channel.ID)
+	refreshChannelDailyStats(db)
 
 	return nil
 }
@@ -175,6 +192,7 @@ func refreshChannelStats(db *sql.DB, channelId string) {
 	}
 
 	fmt.Printf("Statistiques mises à jour avec succès pour channel_id '%s'.\n", channelId)
+	detectChannelMilestones(db, dbChannelID)
 }
 
 func updateAllChannelStats(db *sql.DB, _ time.Duration) {
@@ -194,6 +212,8 @@ func updateAllChannelStats(db *sql.DB, _ time.Duration) {
 
 		refreshChannelStats(db, channelID)
 	}
+
+	refreshChannelDailyStats(db)
 }
 
 func callRoutePeriodically(task func(*sql.DB, time.Duration), interval time.Duration, dbConn *sql.DB) {
@@ -221,9 +241,10 @@ func recuperateChannelFromDB(db *sql.DB, channelId string) (int, error) {
 	return dbChannelID, nil
 }
 
-func AddNewVideo(db *sql.DB, videoId string, channelId string) error {
+// Insère la vidéo, ou met à jour ses métadonnées si elle est déjà suivie (titre modifié, re-notification du hub).
+func AddNewVideo(dbConn *sql.DB, videoId string, channelId string, discoveredVia string) error {
 	data, err := youtube.YouTubeAPIRequest("videos", map[string]string{
-		"part":       "snippet",
+		"part":       "snippet,liveStreamingDetails",
 		"id":         videoId,
 		"maxResults": "1",
 	})
@@ -231,27 +252,16 @@ func AddNewVideo(db *sql.DB, videoId string, channelId string) error {
 		return fmt.Errorf("Erreur lors de l'appel à l'API YouTube pour video_id '%s': %v\n", videoId, err)
 	}
 
-	query := `SELECT id FROM channels WHERE channel_id = $1;`
-	var dbChannelID int
-	err = db.QueryRow(query, channelId).Scan(&dbChannelID)
-
-	if err != nil {
-		return fmt.Errorf("Erreur lors de l'appel à l'API YouTube pour video_id '%s': %v\n", videoId, err)
-	}
-
 	var videoData config.YouTubeVideo
 	if err := mapToStruct(data, &videoData); err != nil {
 		return fmt.Errorf("Erreur lors du traitement des données de la vidéo : %v\n", err)
 	}
 
-	var isaShort bool = isShort(videoId)
+	if len(videoData.Items) == 0 {
+		return fmt.Errorf("Aucune donnée trouvée pour la vidéo avec video_id '%s'", videoId)
+	}
 
 	video := videoData.Items[0]
-	query = `
-		INSERT INTO videos (video_id, channel_id, title, description, published_at, thumbnail_url, is_short)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id;
-	`
 
 	var bestThumbnail string
 	if video.Snippet.Thumbnails.High.URL != "" {
@@ -262,79 +272,69 @@ func AddNewVideo(db *sql.DB, videoId string, channelId string) error {
 		bestThumbnail = video.Snippet.Thumbnails.Default.URL
 	}
 
-	var id string
-	err = db.QueryRow(query, video.ID, dbChannelID, video.Snippet.Title, video.Snippet.Description, video.Snippet.PublishedAt, bestThumbnail, isaShort).Scan(&id)
+	existing, err := db.AreVideosInBDD(dbConn, []string{videoId})
 	if err != nil {
-		fmt.Printf("Erreur lors de l'insertion en base de données : %v\n", err)
-		return fmt.Errorf("Erreur lors de l'insertion des statistiques en base pour channel_id '%s': %v\n", video.ID, err)
+		return fmt.Errorf("Erreur lors de la recherche de la vidéo en base de données : %v", err)
+	}
+	if existing[videoId] {
+		_, err := db.UpdateVideoMetadata(dbConn, videoId, video.Snippet.Title, video.Snippet.Description, video.Snippet.PublishedAt, bestThumbnail, video.Snippet.CategoryID)
+		if err != nil {
+			return fmt.Errorf("Erreur lors de la mise à jour de la vidéo '%s' : %v", videoId, err)
+		}
+		fmt.Printf("Vidéo mise à jour avec succès pour video_id '%s'.\n", videoId)
+		return nil
 	}
 
-	fmt.Printf("Vidéo ajoutée avec succès pour video_id '%s' avec l'ID '%s'.\n", videoId, id)
-	ScanVideoStats(db, id, videoId)
-
-	return nil
-}
+	query := `SELECT id FROM channels WHERE channel_id = $1;`
+	var dbChannelID int
+	err = dbConn.QueryRow(query, channelId).Scan(&dbChannelID)
 
-func isShort(videoId string) bool {
-	data, err := youtube.YouTubeAPIRequest("videos", map[string]string{
-		"part":       "contentDetails",
-		"id":         videoId,
-		"maxResults": "1",
-	})
 	if err != nil {
-		fmt.Printf("Erreur lors de l'appel à l'API YouTube pour video_id '%s': %v\n", videoId, err)
-		return false
+		return fmt.Errorf("Erreur lors de l'appel à l'API YouTube pour video_id '%s': %v\n", videoId, err)
 	}
 
-	var videoContentDetails config.YouTubeVideoContentDetails
-	if err := mapToStruct(data, &videoContentDetails); err != nil {
-		fmt.Printf("Erreur lors du traitement des données de la vidéo : %v\n", err)
-		return false
-	}
+	seconds, short := classifyVideo(videoId)
 
-	duration := videoContentDetails.Items[0].ContentDetails.Duration
-	re := regexp.MustCompile(`PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?`)
-	matches := re.FindStringSubmatch(duration)
-	if len(matches) == 0 {
-		fmt.Printf("Durée non reconnue pour la vidéo ID %s : %s\n", videoId, duration)
-		return false
-	}
+	query = `
+		INSERT INTO videos (video_id, channel_id, title, description, published_at, thumbnail_url, is_short, short_source, short_confidence, duration_seconds, discovered_via, category_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''))
+		ON CONFLICT (video_id) DO UPDATE SET
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			published_at = EXCLUDED.published_at,
+			thumbnail_url = EXCLUDED.thumbnail_url,
+			category_id = COALESCE(EXCLUDED.category_id, videos.category_id),
+			removed_at = NULL
+		RETURNING id;
+	`
 
-	heuresInt, minutesInt, secondsInt := 0, 0, 0
-	if matches[1] != "" {
-		heuresInt, err = strconv.Atoi(matches[1])
-		if err != nil {
-			fmt.Printf("Erreur lors de la conversion des heures : %v\n", err)
-			return false
-		}
+	var id string
+	err = dbConn.QueryRow(query, video.ID, dbChannelID, video.Snippet.Title, video.Snippet.Description, video.Snippet.PublishedAt, bestThumbnail, short.IsShort, short.Source, short.Confidence, seconds, discoveredVia, video.Snippet.CategoryID).Scan(&id)
+	if err != nil {
+		fmt.Printf("Erreur lors de l'insertion en base de données : %v\n", err)
+		return fmt.Errorf("Erreur lors de l'insertion des statistiques en base pour channel_id '%s': %v\n", video.ID, err)
 	}
-	if matches[2] != "" {
-		minutesInt, err = strconv.Atoi(matches[2])
-		if err != nil {
-			fmt.Printf("Erreur lors de la conversion des minutes : %v\n", err)
-			return false
-		}
+
+	fmt.Printf("Vidéo ajoutée avec succès pour video_id '%s' avec l'ID '%s'.\n", videoId, id)
+
+	kind, err := saveLiveDetails(dbConn, videoId, video.Snippet.LiveBroadcastContent, video.LiveStreamingDetails, seconds)
+	if err != nil {
+		fmt.Printf("Erreur lors de l'enregistrement des informations de direct pour video_id '%s' : %v\n", videoId, err)
 	}
-	if matches[3] != "" {
-		secondsInt, err = strconv.Atoi(matches[3])
-		if err != nil {
-			fmt.Printf("Erreur lors de la conversion des secondes : %v\n", err)
-			return false
-		}
+	// Un direct ou une première à venir n'a pas encore de statistiques utiles : le suivi des directs s'en charge.
+	if kind != LiveTypeUpcoming && kind != LiveTypePremiere {
+		ScanVideoStats(dbConn, id, videoId)
 	}
 
-	fmt.Printf("heures minutes et secondes %d %d %d\n", heuresInt, minutesInt, secondsInt)
-	if minutesInt <= 1 && secondsInt <= 60 {
-		return true
-	}
-	return false
+	return nil
 }
 
 func refreshWithFrequency(db *sql.DB, frequency time.Duration) {
 	interval := fmt.Sprintf("%02d:%02d:%02d", int(frequency.Hours()), int(frequency.Minutes())%60, int(frequency.Seconds())%60)
 	query := `
 			SELECT id, video_id FROM videos
-			WHERE refreshed_frequency = $1;
+			WHERE refreshed_frequency = $1 AND removed_at IS NULL
+				AND NOT (live_type IN ('upcoming', 'premiere') AND actual_start_at IS NULL);
 			`
 
 	rows, err := db.Query(query, interval)
@@ -389,4 +389,6 @@ func ScanVideoStats(db *sql.DB, id string, videoId string) {
 	}
 
 	fmt.Printf("Statistiques mises à jour avec succès pour video_id '%s'.\n", videoId)
+	refreshVideoPrediction(db, videoId)
+	detectVideoMilestones(db, id)
 }
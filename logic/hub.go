@@ -0,0 +1,291 @@
+package logic
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"ytst-back/config"
+	"ytst-back/db"
+	"ytst-back/metrics"
+)
+
+const (
+	hubLeaseSeconds    = 864000
+	hubRenewalWindow   = 24 * time.Hour
+	hubRenewalInterval = 1 * time.Hour
+	hubDenialBackoff   = 24 * time.Hour
+)
+
+var (
+	hubURL         string
+	callbackURL    string
+	hubVerifyToken string
+)
+
+func configureHub(cfg *config.Config) {
+	hubURL = cfg.HubURL
+	callbackURL = cfg.CallbackURL
+	hubVerifyToken = cfg.HubVerifyToken
+}
+
+var hubSignatureAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+var (
+	ErrUnknownTopic     = errors.New("topic inconnu")
+	ErrSignatureMissing = errors.New("signature X-Hub-Signature absente")
+	ErrSignatureInvalid = errors.New("signature X-Hub-Signature invalide")
+)
+
+func hubTopicURL(channelId string) string {
+	return "https://www.youtube.com/xml/feeds/videos.xml?channel_id=" + channelId
+}
+
+func SubscribeChannel(dbConn *sql.DB, channelId string) error {
+	topic := hubTopicURL(channelId)
+
+	secret, err := newHubSecret()
+	if err != nil {
+		return fmt.Errorf("erreur lors de la génération du secret : %v", err)
+	}
+
+	secret, err = db.SaveHubSubscriptionRequest(dbConn, channelId, topic, callbackURL, secret, hubLeaseSeconds)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement de l'abonnement : %v", err)
+	}
+
+	if err := postHubRequest("subscribe", topic, secret); err != nil {
+		if dbErr := db.SetHubSubscriptionStatus(dbConn, topic, callbackURL, "error", err.Error()); dbErr != nil {
+			log.Printf("Erreur lors de l'enregistrement de l'échec d'abonnement : %v", dbErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func newHubSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func postHubRequest(mode string, topic string, secret string) error {
+	form := url.Values{}
+	form.Add("hub.mode", mode)
+	form.Add("hub.topic", topic)
+	form.Add("hub.callback", callbackURL)
+	form.Add("hub.lease_seconds", strconv.Itoa(hubLeaseSeconds))
+	form.Add("hub.verify", "async")
+	form.Add("hub.verify_token", hubVerifyToken)
+	if secret != "" {
+		form.Add("hub.secret", secret)
+	}
+
+	resp, err := http.PostForm(hubURL, form)
+	if err != nil {
+		return fmt.Errorf("erreur %s: %v", mode, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("status: %d, body: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+func UnsubscribeChannel(dbConn *sql.DB, channelId string) error {
+	topic := hubTopicURL(channelId)
+
+	if _, err := db.HubSubscriptionStatus(dbConn, topic, callbackURL); err != nil {
+		return fmt.Errorf("aucun abonnement pour la chaîne '%s' : %v", channelId, err)
+	}
+	if err := db.SetHubSubscriptionStatus(dbConn, topic, callbackURL, "pending_unsubscribe", ""); err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement du désabonnement : %v", err)
+	}
+
+	if err := postHubRequest("unsubscribe", topic, ""); err != nil {
+		if dbErr := db.SetHubSubscriptionStatus(dbConn, topic, callbackURL, "error", err.Error()); dbErr != nil {
+			log.Printf("Erreur lors de l'enregistrement de l'échec de désabonnement : %v", dbErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// Répond à la requête de vérification du hub : true seulement si elle correspond
+// à un abonnement (ou désabonnement) que nous avons réellement demandé.
+func ConfirmHubVerification(dbConn *sql.DB, mode string, topic string, verifyToken string, leaseSeconds string) bool {
+	expectedToken := hubVerifyToken
+	if subtle.ConstantTimeCompare([]byte(verifyToken), []byte(expectedToken)) != 1 {
+		log.Printf("Vérification %s refusée pour '%s' : verify_token invalide", mode, topic)
+		return false
+	}
+
+	status, err := db.HubSubscriptionStatus(dbConn, topic, callbackURL)
+	if err != nil {
+		log.Printf("Vérification %s refusée pour '%s' : abonnement inconnu", mode, topic)
+		return false
+	}
+
+	switch mode {
+	case "subscribe":
+		if status != "pending" && status != "active" {
+			log.Printf("Vérification subscribe refusée pour '%s' : statut '%s'", topic, status)
+			return false
+		}
+		lease, err := strconv.Atoi(leaseSeconds)
+		if err != nil || lease <= 0 {
+			log.Printf("Vérification subscribe refusée pour '%s' : hub.lease_seconds invalide '%s'", topic, leaseSeconds)
+			return false
+		}
+		if err := db.MarkHubSubscriptionVerified(dbConn, topic, callbackURL, lease); err != nil {
+			log.Printf("Erreur lors de l'enregistrement de la vérification pour '%s' : %v", topic, err)
+			return false
+		}
+		return true
+	case "unsubscribe":
+		if status != "pending_unsubscribe" {
+			log.Printf("Vérification unsubscribe refusée pour '%s' : statut '%s'", topic, status)
+			return false
+		}
+		if err := db.SetHubSubscriptionStatus(dbConn, topic, callbackURL, "unsubscribed", ""); err != nil {
+			log.Printf("Erreur lors de l'enregistrement du désabonnement pour '%s' : %v", topic, err)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+func RecordHubDenial(dbConn *sql.DB, topic string, reason string) {
+	// La requête n'est pas signée : un refus n'est accepté que pour une demande en attente.
+	status, err := db.HubSubscriptionStatus(dbConn, topic, callbackURL)
+	if err != nil || status != "pending" {
+		log.Printf("Refus ignoré pour '%s' : aucune demande en attente", topic)
+		return
+	}
+	log.Printf("Abonnement refusé par le hub pour '%s' : %s", topic, reason)
+	if reason == "" {
+		reason = "denied"
+	}
+	if err := db.SetHubSubscriptionStatus(dbConn, topic, callbackURL, "denied", reason); err != nil {
+		log.Printf("Erreur lors de l'enregistrement du refus pour '%s' : %v", topic, err)
+	}
+}
+
+// Le topic est lu dans l'en-tête Link rel="self" envoyé par le hub, à défaut dans le flux Atom.
+func NotificationTopic(linkHeaders []string, body []byte) string {
+	for _, header := range linkHeaders {
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 {
+				continue
+			}
+			for _, param := range parts[1:] {
+				if strings.ReplaceAll(strings.TrimSpace(param), `"`, "") == "rel=self" {
+					return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+				}
+			}
+		}
+	}
+
+	var feed config.Feed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return ""
+	}
+	for _, link := range feed.Links {
+		if link.Rel == "self" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// Vérifie la signature HMAC d'une notification avec le secret enregistré pour son topic.
+// Toute erreur signifie que la notification doit être ignorée.
+func VerifyHubNotification(dbConn *sql.DB, topic string, signature string, body []byte) error {
+	metrics.HubNotifications.Add(metrics.NotificationReceived, 1)
+
+	secret, err := db.HubSubscriptionSecret(dbConn, topic, callbackURL)
+	if err != nil || secret == "" {
+		metrics.HubNotifications.Add(metrics.NotificationUnknownTopic, 1)
+		return fmt.Errorf("%w : '%s'", ErrUnknownTopic, topic)
+	}
+
+	if signature == "" {
+		metrics.HubNotifications.Add(metrics.NotificationSignatureMissing, 1)
+		return ErrSignatureMissing
+	}
+
+	if !validHubSignature(secret, signature, body) {
+		metrics.HubNotifications.Add(metrics.NotificationSignatureInvalid, 1)
+		return ErrSignatureInvalid
+	}
+
+	metrics.HubNotifications.Add(metrics.NotificationAccepted, 1)
+	return nil
+}
+
+func validHubSignature(secret string, signature string, body []byte) bool {
+	algorithm, digest, found := strings.Cut(signature, "=")
+	if !found {
+		return false
+	}
+
+	newHash, ok := hubSignatureAlgorithms[strings.ToLower(algorithm)]
+	if !ok {
+		return false
+	}
+
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func RenewHubSubscriptions(dbConn *sql.DB, _ time.Duration) {
+	channelIDs, err := db.ChannelsWithSubscriptionToRenew(dbConn, callbackURL, hubRenewalWindow, hubDenialBackoff)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des abonnements à renouveler : %v", err)
+		return
+	}
+
+	for _, channelID := range channelIDs {
+		if err := SubscribeChannel(dbConn, channelID); err != nil {
+			log.Printf("Erreur de renouvellement d'abonnement pour la chaîne %s : %v", channelID, err)
+		} else {
+			log.Printf("Renouvellement d'abonnement demandé pour la chaîne: %s", channelID)
+		}
+	}
+}
+
+func HubSubscriptions(dbConn *sql.DB) ([]config.HubSubscription, error) {
+	return db.HubSubscriptions(dbConn, callbackURL, hubRenewalWindow)
+}
@@ -0,0 +1,99 @@
+package logic
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+func sign(newHash func() hash.Hash, secret string, body []byte) string {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidHubSignature(t *testing.T) {
+	secret := "s3cr3t"
+	body := []byte("<feed></feed>")
+
+	tests := []struct {
+		name      string
+		signature string
+		want      bool
+	}{
+		{"sha1 valide", "sha1=" + sign(sha1.New, secret, body), true},
+		{"sha256 valide", "sha256=" + sign(sha256.New, secret, body), true},
+		{"algorithme en majuscules", "SHA1=" + sign(sha1.New, secret, body), true},
+		{"mauvais secret", "sha1=" + sign(sha1.New, "autre", body), false},
+		{"mauvais corps", "sha256=" + sign(sha256.New, secret, []byte("<feed/>")), false},
+		{"algorithme ne correspondant pas au condensat", "sha256=" + sign(sha1.New, secret, body), false},
+		{"algorithme inconnu", "md5=" + sign(sha1.New, secret, body), false},
+		{"hexadécimal invalide", "sha1=zz" + sign(sha1.New, secret, body)[2:], false},
+		{"sans algorithme", sign(sha1.New, secret, body), false},
+		{"en-tête absent", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validHubSignature(secret, tt.signature, body); got != tt.want {
+				t.Errorf("validHubSignature(%q) = %v, attendu %v", tt.signature, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationTopic(t *testing.T) {
+	const topic = "https://www.youtube.com/xml/feeds/videos.xml?channel_id=UCtest"
+	feed := []byte(`<?xml version='1.0' encoding='UTF-8'?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <link rel="hub" href="https://pubsubhubbub.appspot.com"/>
+  <link rel="self" href="https://www.youtube.com/xml/feeds/videos.xml?channel_id=UCfeed"/>
+</feed>`)
+
+	tests := []struct {
+		name  string
+		links []string
+		body  []byte
+		want  string
+	}{
+		{
+			name:  "en-têtes Link séparés",
+			links: []string{"<https://pubsubhubbub.appspot.com>; rel=hub", "<" + topic + ">; rel=self"},
+			body:  feed,
+			want:  topic,
+		},
+		{
+			name:  "un seul en-tête, rel entre guillemets",
+			links: []string{`<https://pubsubhubbub.appspot.com>; rel="hub", <` + topic + `>; rel="self"`},
+			body:  feed,
+			want:  topic,
+		},
+		{
+			name:  "sans rel=self, lien du flux",
+			links: []string{"<https://pubsubhubbub.appspot.com>; rel=hub"},
+			body:  feed,
+			want:  "https://www.youtube.com/xml/feeds/videos.xml?channel_id=UCfeed",
+		},
+		{
+			name: "sans en-tête, lien du flux",
+			body: feed,
+			want: "https://www.youtube.com/xml/feeds/videos.xml?channel_id=UCfeed",
+		},
+		{
+			name: "corps illisible",
+			body: []byte("pas du xml"),
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NotificationTopic(tt.links, tt.body); got != tt.want {
+				t.Errorf("NotificationTopic() = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
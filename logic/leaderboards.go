@@ -0,0 +1,46 @@
+package logic
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const MaxLeaderboardPageSize = 100
+
+var ErrUnknownLeaderboardMetric = errors.New("métrique de classement inconnue")
+
+func ChannelLeaderboard(dbConn *sql.DB, metric string, window time.Duration, filters config.LeaderboardFilters, page int, pageSize int) (config.ChannelLeaderboard, error) {
+	column, ok := db.ChannelLeaderboardColumns[metric]
+	if !ok {
+		return config.ChannelLeaderboard{}, fmt.Errorf("%w : '%s'", ErrUnknownLeaderboardMetric, metric)
+	}
+
+	entries, total, err := db.ChannelLeaderboard(dbConn, column, window, filters, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return config.ChannelLeaderboard{}, err
+	}
+	return config.ChannelLeaderboard{
+		LeaderboardPage: config.LeaderboardPage{Metric: metric, Window: window.String(), Page: page, PageSize: pageSize, Total: total},
+		Entries:         entries,
+	}, nil
+}
+
+func VideoLeaderboard(dbConn *sql.DB, metric string, window time.Duration, filters config.LeaderboardFilters, page int, pageSize int) (config.VideoLeaderboard, error) {
+	column, ok := db.VideoLeaderboardColumns[metric]
+	if !ok {
+		return config.VideoLeaderboard{}, fmt.Errorf("%w : '%s'", ErrUnknownLeaderboardMetric, metric)
+	}
+
+	entries, total, err := db.VideoLeaderboard(dbConn, column, window, filters, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return config.VideoLeaderboard{}, err
+	}
+	return config.VideoLeaderboard{
+		LeaderboardPage: config.LeaderboardPage{Metric: metric, Window: window.String(), Page: page, PageSize: pageSize, Total: total},
+		Entries:         entries,
+	}, nil
+}
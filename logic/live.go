@@ -0,0 +1,138 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"ytst-back/config"
+	"ytst-back/db"
+	"ytst-back/youtube"
+)
+
+const (
+	LiveTypeNone      = "none"
+	LiveTypeUpcoming  = "upcoming"
+	LiveTypeLive      = "live"
+	LiveTypeCompleted = "completed"
+	LiveTypePremiere  = "premiere"
+)
+
+// Une première est une vidéo déjà montée : contrairement à un direct (P0D), sa durée est connue
+// avant la diffusion.
+func liveType(broadcastContent string, details *config.LiveStreamingDetails, durationSeconds *int) string {
+	if details == nil {
+		return LiveTypeNone
+	}
+	if details.ActualEndTime != "" {
+		return LiveTypeCompleted
+	}
+
+	premiere := durationSeconds != nil && *durationSeconds > 0
+	switch broadcastContent {
+	case "upcoming":
+		if premiere {
+			return LiveTypePremiere
+		}
+		return LiveTypeUpcoming
+	case "live":
+		if premiere {
+			return LiveTypePremiere
+		}
+		return LiveTypeLive
+	}
+	return LiveTypeCompleted
+}
+
+func saveLiveDetails(dbConn *sql.DB, videoId string, broadcastContent string, details *config.LiveStreamingDetails, durationSeconds *int) (string, error) {
+	kind := liveType(broadcastContent, details, durationSeconds)
+	if details == nil {
+		details = &config.LiveStreamingDetails{}
+	}
+	err := db.UpdateVideoLiveDetails(dbConn, videoId, kind, details.ScheduledStartTime, details.ActualStartTime, details.ActualEndTime)
+	return kind, err
+}
+
+// Suivi haute fréquence des directs : état, horaires et spectateurs simultanés.
+func pollLiveVideos(dbConn *sql.DB, _ time.Duration) {
+	videos, err := db.LiveVideosToPoll(dbConn)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des directs : %v", err)
+		return
+	}
+
+	for start := 0; start < len(videos); start += 50 {
+		batch := videos[start:min(start+50, len(videos))]
+		if err := pollLiveBatch(dbConn, batch); err != nil {
+			log.Printf("Erreur lors du suivi des directs : %v", err)
+		}
+	}
+}
+
+func pollLiveBatch(dbConn *sql.DB, batch []db.LiveVideo) error {
+	ids := make([]string, len(batch))
+	for i, video := range batch {
+		ids[i] = video.VideoID
+	}
+
+	data, err := youtube.YouTubeAPIRequest("videos", map[string]string{
+		"part":       "snippet,liveStreamingDetails",
+		"id":         strings.Join(ids, ","),
+		"maxResults": "50",
+	})
+	if err != nil {
+		return err
+	}
+
+	var videoData config.YouTubeVideo
+	if err := mapToStruct(data, &videoData); err != nil {
+		return fmt.Errorf("Erreur lors du traitement des données de la vidéo : %v", err)
+	}
+
+	items := make(map[string]int, len(videoData.Items))
+	for i, item := range videoData.Items {
+		items[item.ID] = i
+	}
+
+	for _, video := range batch {
+		i, ok := items[video.VideoID]
+		if !ok {
+			// Vidéo supprimée ou passée en privé : sans cela, elle resterait indéfiniment à suivre.
+			removed, err := db.MarkVideoRemoved(dbConn, video.VideoID, time.Now())
+			if err != nil {
+				log.Printf("Erreur lors de la suppression du direct '%s' : %v", video.VideoID, err)
+			} else if removed {
+				log.Printf("Direct '%s' absent de l'API, marqué comme supprimé", video.VideoID)
+			}
+			continue
+		}
+		item := videoData.Items[i]
+
+		kind, err := saveLiveDetails(dbConn, video.VideoID, item.Snippet.LiveBroadcastContent, item.LiveStreamingDetails, video.DurationSeconds)
+		if err != nil {
+			log.Printf("Erreur lors de la mise à jour du direct '%s' : %v", video.VideoID, err)
+			continue
+		}
+
+		if item.LiveStreamingDetails != nil && item.LiveStreamingDetails.ConcurrentViewers != "" {
+			viewers, err := strconv.Atoi(item.LiveStreamingDetails.ConcurrentViewers)
+			if err == nil {
+				if err := db.InsertLiveViewers(dbConn, video.ID, viewers); err != nil {
+					log.Printf("Erreur lors de l'enregistrement des spectateurs de '%s' : %v", video.VideoID, err)
+				}
+			}
+		}
+
+		if kind == LiveTypeCompleted {
+			log.Printf("Direct '%s' terminé", video.VideoID)
+			ScanVideoStats(dbConn, video.ID, video.VideoID)
+		}
+	}
+	return nil
+}
+
+func LiveStats(dbConn *sql.DB, videoId string) (config.LiveStats, error) {
+	return db.LiveStats(dbConn, videoId)
+}
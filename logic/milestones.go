@@ -0,0 +1,173 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const (
+	MilestoneSubscribers  = "subscribers"
+	MilestoneChannelViews = "channel_views"
+	MilestoneVideoViews   = "video_views"
+)
+
+var (
+	subscriberMilestones  []int64
+	channelViewMilestones []int64
+	videoViewMilestones   []int64
+	milestoneFeedURL      string
+)
+
+func configureMilestones(cfg *config.Config) {
+	subscriberMilestones = cfg.SubscriberMilestones
+	channelViewMilestones = cfg.ChannelViewMilestones
+	videoViewMilestones = cfg.VideoViewMilestones
+	milestoneFeedURL = cfg.PublicBaseURL + "/ytbtst/milestones.atom"
+}
+
+// Paliers franchis entre deux relevés. Sans relevé précédent (nouvelle chaîne ou vidéo),
+// rien n'est signalé : les paliers déjà dépassés ne sont pas des événements.
+func crossedMilestones(ladder []int64, previous *int64, current *int64) []int64 {
+	if previous == nil || current == nil {
+		return nil
+	}
+	var crossed []int64
+	for _, threshold := range ladder {
+		if *previous < threshold && *current >= threshold {
+			crossed = append(crossed, threshold)
+		}
+	}
+	return crossed
+}
+
+// Appelée après chaque insertion dans channel_stats.
+func detectChannelMilestones(dbConn *sql.DB, channelDBID int) {
+	samples, err := db.LastChannelSamples(dbConn, channelDBID)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des relevés de la chaîne %d : %v", channelDBID, err)
+		return
+	}
+	if len(samples) < 2 {
+		return
+	}
+	current, previous := samples[0], samples[1]
+
+	for _, m := range []struct {
+		metric   string
+		ladder   []int64
+		previous *int64
+		current  *int64
+	}{
+		{MilestoneSubscribers, subscriberMilestones, previous.Subscribers, current.Subscribers},
+		{MilestoneChannelViews, channelViewMilestones, previous.Views, current.Views},
+	} {
+		for _, threshold := range crossedMilestones(m.ladder, m.previous, m.current) {
+			inserted, err := db.SaveChannelMilestone(dbConn, channelDBID, m.metric, threshold, *m.current, current.RecordedAt)
+			if err != nil {
+				log.Printf("Erreur lors de l'enregistrement du palier %d (%s) de la chaîne %d : %v", threshold, m.metric, channelDBID, err)
+			} else if inserted {
+				log.Printf("Palier %d (%s) franchi par la chaîne %d", threshold, m.metric, channelDBID)
+			}
+		}
+	}
+}
+
+// Appelée après chaque insertion dans video_stats par ScanVideoStats.
+func detectVideoMilestones(dbConn *sql.DB, id string) {
+	videoDBID, err := strconv.Atoi(id)
+	if err != nil {
+		return
+	}
+	samples, err := db.LastVideoSamples(dbConn, videoDBID)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des relevés de la vidéo %d : %v", videoDBID, err)
+		return
+	}
+	if len(samples) < 2 {
+		return
+	}
+	current, previous := samples[0], samples[1]
+
+	for _, threshold := range crossedMilestones(videoViewMilestones, previous.Views, current.Views) {
+		inserted, err := db.SaveVideoMilestone(dbConn, videoDBID, MilestoneVideoViews, threshold, *current.Views, current.RecordedAt)
+		if err != nil {
+			log.Printf("Erreur lors de l'enregistrement du palier %d de la vidéo %d : %v", threshold, videoDBID, err)
+		} else if inserted {
+			log.Printf("Palier %d de vues franchi par la vidéo %d", threshold, videoDBID)
+		}
+	}
+}
+
+func MilestoneEvents(dbConn *sql.DB, channelId string, limit int) ([]config.MilestoneEvent, error) {
+	return db.MilestoneEvents(dbConn, channelId, limit)
+}
+
+func MilestoneFeed(dbConn *sql.DB, channelId string, limit int) (config.MilestoneAtomFeed, error) {
+	events, err := db.MilestoneEvents(dbConn, channelId, limit)
+	if err != nil {
+		return config.MilestoneAtomFeed{}, err
+	}
+
+	selfURL := milestoneFeedURL
+	if channelId != "" {
+		selfURL += "?channelId=" + channelId
+	}
+	feed := config.MilestoneAtomFeed{
+		ID:      selfURL,
+		Title:   "Paliers franchis",
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Links:   []config.AtomLink{{Rel: "self", Href: selfURL}},
+	}
+	if len(events) > 0 {
+		feed.Updated = events[0].ReachedAt
+	}
+
+	for _, e := range events {
+		entry := config.MilestoneAtomEntry{
+			ID:      fmt.Sprintf("%s#%d", milestoneFeedURL, e.ID),
+			Title:   milestoneTitle(e),
+			Updated: e.ReachedAt,
+			Summary: fmt.Sprintf("%s : %s (palier %s)", milestoneTitle(e), groupThousands(e.Value), groupThousands(e.Threshold)),
+		}
+		if e.VideoID != nil {
+			entry.Links = []config.AtomLink{{Rel: "alternate", Href: "https://www.youtube.com/watch?v=" + *e.VideoID}}
+		} else {
+			entry.Links = []config.AtomLink{{Rel: "alternate", Href: "https://www.youtube.com/channel/" + e.ChannelID}}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed, nil
+}
+
+func milestoneTitle(e config.MilestoneEvent) string {
+	threshold := groupThousands(e.Threshold)
+	switch e.Metric {
+	case MilestoneSubscribers:
+		return fmt.Sprintf("%s dépasse %s abonnés", e.ChannelName, threshold)
+	case MilestoneChannelViews:
+		return fmt.Sprintf("%s dépasse %s vues", e.ChannelName, threshold)
+	}
+	title := e.ChannelName
+	if e.VideoTitle != nil {
+		title = *e.VideoTitle
+	}
+	return fmt.Sprintf("« %s » dépasse %s vues", title, threshold)
+}
+
+func groupThousands(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
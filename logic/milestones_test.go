@@ -0,0 +1,40 @@
+package logic
+
+import (
+	"slices"
+	"testing"
+)
+
+func milestoneValue(v int64) *int64 {
+	return &v
+}
+
+func TestCrossedMilestones(t *testing.T) {
+	ladder := []int64{1000, 10000, 100000, 1000000}
+
+	tests := []struct {
+		name     string
+		ladder   []int64
+		previous *int64
+		current  *int64
+		want     []int64
+	}{
+		{"pas de relevé précédent", ladder, nil, milestoneValue(50000), nil},
+		{"pas de relevé courant", ladder, milestoneValue(500), nil, nil},
+		{"aucun palier", ladder, milestoneValue(1200), milestoneValue(9000), nil},
+		{"un palier", ladder, milestoneValue(9000), milestoneValue(12000), []int64{10000}},
+		{"plusieurs paliers d'un coup", ladder, milestoneValue(500), milestoneValue(150000), []int64{1000, 10000, 100000}},
+		{"valeur égale au palier", ladder, milestoneValue(999), milestoneValue(1000), []int64{1000}},
+		{"palier déjà atteint", ladder, milestoneValue(1000), milestoneValue(1500), nil},
+		{"baisse sous un palier", ladder, milestoneValue(10500), milestoneValue(9500), nil},
+		{"échelle vide", nil, milestoneValue(0), milestoneValue(1000000), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := crossedMilestones(tt.ladder, tt.previous, tt.current); !slices.Equal(got, tt.want) {
+				t.Errorf("crossedMilestones() = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package logic
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const (
+	notificationMaxAttempts   = 5
+	notificationGracePeriod   = 1 * time.Minute
+	notificationSweepInterval = 5 * time.Minute
+)
+
+var notificationQueue = make(chan int, 256)
+
+func ParseHubFeed(body []byte) (config.Feed, error) {
+	var feed config.Feed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return feed, err
+	}
+	if len(feed.Entry) == 0 && len(feed.DeletedEntry) == 0 {
+		return feed, fmt.Errorf("aucune entrée dans la notification")
+	}
+	return feed, nil
+}
+
+// Persiste la notification brute puis la confie au worker ; le traitement est asynchrone.
+func EnqueueHubNotification(dbConn *sql.DB, topic string, body []byte) error {
+	id, err := db.SaveHubNotification(dbConn, topic, body)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement de la notification : %v", err)
+	}
+
+	select {
+	case notificationQueue <- id:
+	default:
+		log.Printf("File de notifications pleine, la notification %d sera reprise plus tard", id)
+	}
+	return nil
+}
+
+func StartNotificationWorker(dbConn *sql.DB) {
+	go func() {
+		for id := range notificationQueue {
+			if err := ProcessHubNotification(dbConn, id, false); err != nil {
+				log.Printf("Erreur lors du traitement de la notification %d : %v", id, err)
+			}
+		}
+	}()
+	go requeuePendingNotifications(dbConn, 0)
+	callRoutePeriodically(requeuePendingNotifications, notificationSweepInterval, dbConn)
+}
+
+func requeuePendingNotifications(dbConn *sql.DB, _ time.Duration) {
+	ids, err := db.PendingHubNotificationIDs(dbConn, notificationMaxAttempts, notificationGracePeriod.Seconds())
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des notifications en attente : %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		select {
+		case notificationQueue <- id:
+		default:
+			return
+		}
+	}
+}
+
+// Traite une notification enregistrée. Avec force, la déduplication est ignorée (rejeu manuel).
+func ProcessHubNotification(dbConn *sql.DB, id int, force bool) error {
+	notification, err := db.HubNotification(dbConn, id)
+	if err != nil {
+		return err
+	}
+	if notification.Status == "processed" && !force {
+		return nil
+	}
+
+	feed, err := ParseHubFeed([]byte(notification.Body))
+	if err == nil {
+		err = processHubFeed(dbConn, feed, id, force)
+	}
+
+	status, lastError := "processed", ""
+	if err != nil {
+		status, lastError = "failed", err.Error()
+	}
+	if dbErr := db.FinishHubNotification(dbConn, id, status, lastError); dbErr != nil {
+		return dbErr
+	}
+	return err
+}
+
+func processHubFeed(dbConn *sql.DB, feed config.Feed, notificationID int, force bool) error {
+	var errs []error
+
+	for _, entry := range feed.Entry {
+		claimed, err := claimVideoUpdate(dbConn, entry.VideoId, entry.Updated, notificationID, force)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := AddNewVideo(dbConn, entry.VideoId, entry.ChannelId, DiscoveredViaPush); err != nil {
+			errs = append(errs, err)
+			releaseVideoUpdate(dbConn, entry.VideoId, entry.Updated)
+		}
+	}
+
+	for _, deleted := range feed.DeletedEntry {
+		claimed, err := claimVideoUpdate(dbConn, deleted.VideoId(), deleted.When, notificationID, force)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		removedAt, err := time.Parse(time.RFC3339Nano, deleted.When)
+		if err != nil {
+			removedAt = time.Now()
+		}
+
+		removed, err := db.MarkVideoRemoved(dbConn, deleted.VideoId(), removedAt)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("erreur lors de la suppression de la vidéo '%s' : %v", deleted.VideoId(), err))
+			releaseVideoUpdate(dbConn, deleted.VideoId(), deleted.When)
+			continue
+		}
+		if removed {
+			log.Printf("Vidéo '%s' de la chaîne '%s' marquée comme supprimée", deleted.VideoId(), deleted.ChannelId())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func claimVideoUpdate(dbConn *sql.DB, videoId string, updated string, notificationID int, force bool) (bool, error) {
+	if force {
+		return true, nil
+	}
+	claimed, err := db.ClaimVideoUpdate(dbConn, videoId, updated, notificationID)
+	if err != nil {
+		return false, fmt.Errorf("erreur lors de la déduplication de la vidéo '%s' : %v", videoId, err)
+	}
+	return claimed, nil
+}
+
+func releaseVideoUpdate(dbConn *sql.DB, videoId string, updated string) {
+	if err := db.ReleaseVideoUpdate(dbConn, videoId, updated); err != nil {
+		log.Printf("Erreur lors de la libération de la vidéo '%s' : %v", videoId, err)
+	}
+}
+
+func HubNotifications(dbConn *sql.DB, status string, limit int) ([]config.HubNotification, error) {
+	return db.HubNotifications(dbConn, status, limit)
+}
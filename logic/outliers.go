@@ -0,0 +1,122 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"time"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+var OutlierAges = []time.Duration{6 * time.Hour, 24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
+const (
+	minOutlierPeers      = 3
+	outlierScoreInterval = 6 * time.Hour
+	// Si le premier relevé suit la publication de moins de 24h, on suppose 0 vue à la publication
+	// pour pouvoir interpoler les premiers âges.
+	publicationAnchorWindow = 24 * time.Hour
+)
+
+func viewSeries(h db.VideoViewHistory) []analytics.Point {
+	points := make([]analytics.Point, 0, len(h.Views)+1)
+	if len(h.RecordedAt) > 0 && h.RecordedAt[0].After(h.PublishedAt) && h.RecordedAt[0].Sub(h.PublishedAt) <= publicationAnchorWindow {
+		points = append(points, analytics.Point{Time: h.PublishedAt, Value: 0})
+	}
+	for i, views := range h.Views {
+		points = append(points, analytics.Point{Time: h.RecordedAt[i], Value: float64(views)})
+	}
+	return analytics.SortPoints(points)
+}
+
+func viewsAtAge(series []analytics.Point, publishedAt time.Time, age time.Duration) (float64, bool) {
+	return analytics.ValueAt(series, publishedAt.Add(age))
+}
+
+// Compare les vues de chaque vidéo à chaque âge à celles des autres vidéos de la même chaîne et du même format
+// (Shorts ou vidéos longues) au même âge.
+// Les rapports de croissance utilisés par les prédictions sont recalculés au passage.
+func computeOutlierScores(dbConn *sql.DB, _ time.Duration) {
+	histories, err := db.VideoViewHistories(dbConn, "", "")
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des historiques de vues : %v", err)
+		return
+	}
+
+	type videoViews struct {
+		videoDBID int
+		views     map[time.Duration]float64
+	}
+	type peerGroup struct {
+		channelDBID int
+		isShort     bool
+	}
+	byGroup := map[peerGroup][]videoViews{}
+	trajectories := make([]videoTrajectory, 0, len(histories))
+	for _, h := range histories {
+		series := viewSeries(h)
+		trajectories = append(trajectories, videoTrajectory{history: h, series: series})
+		v := videoViews{videoDBID: h.VideoDBID, views: map[time.Duration]float64{}}
+		for _, age := range OutlierAges {
+			if views, ok := viewsAtAge(series, h.PublishedAt, age); ok {
+				v.views[age] = views
+			}
+		}
+		group := peerGroup{channelDBID: h.ChannelDBID, isShort: h.IsShort}
+		byGroup[group] = append(byGroup[group], v)
+	}
+	refreshGrowthRatios(trajectories)
+
+	saved := 0
+	for group, videos := range byGroup {
+		for _, age := range OutlierAges {
+			for i, video := range videos {
+				views, ok := video.views[age]
+				if !ok {
+					continue
+				}
+
+				var peers []float64
+				for j, other := range videos {
+					if peerViews, ok := other.views[age]; ok && j != i {
+						peers = append(peers, peerViews)
+					}
+				}
+				if len(peers) < minOutlierPeers {
+					continue
+				}
+
+				median := analytics.Median(peers)
+				var multiplier *float64
+				if median > 0 {
+					m := views / median
+					multiplier = &m
+				}
+				err := db.SaveOutlierScore(dbConn, video.videoDBID, group.isShort, int(age.Hours()), int64(math.Round(views)),
+					median, analytics.Percentile(peers, 0.1), analytics.Percentile(peers, 0.9), len(peers), multiplier)
+				if err != nil {
+					log.Printf("Erreur lors de l'enregistrement du score de la vidéo %d : %v", video.videoDBID, err)
+					continue
+				}
+				saved++
+			}
+		}
+	}
+	log.Printf("Scores de surperformance calculés : %d", saved)
+}
+
+func Outliers(dbConn *sql.DB, age time.Duration, limit int) (config.Outliers, error) {
+	ageHours := int(age.Hours())
+	over, err := db.OutlierScores(dbConn, ageHours, limit, false)
+	if err != nil {
+		return config.Outliers{}, fmt.Errorf("Erreur lors de la récupération des scores : %v", err)
+	}
+	under, err := db.OutlierScores(dbConn, ageHours, limit, true)
+	if err != nil {
+		return config.Outliers{}, fmt.Errorf("Erreur lors de la récupération des scores : %v", err)
+	}
+	return config.Outliers{AgeHours: ageHours, OverPerformers: over, UnderPerformers: under}, nil
+}
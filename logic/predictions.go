@@ -0,0 +1,178 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+	"ytst-back/analytics"
+	"ytst-back/config"
+	"ytst-back/db"
+)
+
+const (
+	PredictionMethodChannel = "channel"
+	PredictionMethodGlobal  = "global"
+
+	minPredictionComparables = 5
+)
+
+var predictionHorizons = []time.Duration{7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
+// channelDBID vaut 0 pour la table globale (toutes chaînes confondues).
+type growthRatioKey struct {
+	channelDBID  int
+	isShort      bool
+	ageHours     int
+	horizonHours int
+}
+
+// Rapports de croissance par chaîne et pour l'ensemble des vidéos, par format, tranche d'âge
+// et horizon, recalculés avec les scores de surperformance plutôt qu'à chaque prédiction.
+var (
+	growthRatiosMu    sync.RWMutex
+	growthRatiosTable = map[growthRatioKey][]float64{}
+)
+
+// Appelée après chaque relevé de ScanVideoStats : résout les prédictions dont l'horizon est
+// atteint, puis prédit les vues à 7 et 30 jours en appliquant au dernier relevé le rapport
+// médian vues(horizon)/vues(âge) observé sur les vidéos du même format de la chaîne à la même
+// tranche d'âge, à défaut sur l'ensemble des vidéos du même format.
+func refreshVideoPrediction(dbConn *sql.DB, videoId string) {
+	history, err := db.VideoViewHistoryOf(dbConn, videoId)
+	if err == sql.ErrNoRows {
+		return
+	}
+	if err != nil {
+		log.Printf("Erreur lors de la récupération de l'historique de la vidéo '%s' : %v", videoId, err)
+		return
+	}
+
+	series := viewSeries(history)
+	last := series[len(series)-1]
+	age := last.Time.Sub(history.PublishedAt)
+
+	for _, horizon := range predictionHorizons {
+		horizonHours := int(horizon.Hours())
+		if age >= horizon {
+			if views, ok := viewsAtAge(series, history.PublishedAt, horizon); ok {
+				if err := db.ResolveVideoPredictions(dbConn, history.VideoDBID, horizonHours, int64(math.Round(views))); err != nil {
+					log.Printf("Erreur lors de la résolution des prédictions de la vidéo '%s' : %v", videoId, err)
+				}
+			}
+			continue
+		}
+		if age <= 0 || last.Value <= 0 {
+			continue
+		}
+
+		method := PredictionMethodChannel
+		ratios := growthRatiosAt(history.ChannelDBID, history.IsShort, age, horizon)
+		if len(ratios) < minPredictionComparables {
+			method = PredictionMethodGlobal
+			ratios = growthRatiosAt(0, history.IsShort, age, horizon)
+		}
+		if len(ratios) < minPredictionComparables {
+			continue
+		}
+
+		prediction := config.VideoPrediction{
+			HorizonHours:   horizonHours,
+			BasisAgeHours:  basisAgeHours(age),
+			BasisViews:     int64(last.Value),
+			PredictedViews: int64(math.Round(last.Value * analytics.Median(ratios))),
+			LowerViews:     int64(math.Round(last.Value * analytics.Percentile(ratios, 0.1))),
+			UpperViews:     int64(math.Round(last.Value * analytics.Percentile(ratios, 0.9))),
+			Method:         method,
+			Comparables:    len(ratios),
+		}
+		if err := db.SaveVideoPrediction(dbConn, history.VideoDBID, prediction); err != nil {
+			log.Printf("Erreur lors de l'enregistrement de la prédiction de la vidéo '%s' : %v", videoId, err)
+		}
+	}
+}
+
+func refreshGrowthRatios(trajectories []videoTrajectory) {
+	groups := map[growthRatioKey][]videoTrajectory{}
+	for _, t := range trajectories {
+		global := growthRatioKey{isShort: t.history.IsShort}
+		channel := growthRatioKey{channelDBID: t.history.ChannelDBID, isShort: t.history.IsShort}
+		groups[global] = append(groups[global], t)
+		groups[channel] = append(groups[channel], t)
+	}
+
+	table := map[growthRatioKey][]float64{}
+	for group, members := range groups {
+		for _, age := range curveAges {
+			for _, horizon := range predictionHorizons {
+				if age >= horizon {
+					continue
+				}
+				if ratios := growthRatios(members, age, horizon); len(ratios) >= minPredictionComparables {
+					key := group
+					key.ageHours, key.horizonHours = int(age.Hours()), int(horizon.Hours())
+					table[key] = ratios
+				}
+			}
+		}
+	}
+
+	growthRatiosMu.Lock()
+	growthRatiosTable = table
+	growthRatiosMu.Unlock()
+}
+
+// Rapports de la tranche d'âge de la vidéo : la vidéo elle-même n'y figure pas,
+// n'ayant pas encore atteint l'horizon.
+func growthRatiosAt(channelDBID int, isShort bool, age time.Duration, horizon time.Duration) []float64 {
+	growthRatiosMu.RLock()
+	defer growthRatiosMu.RUnlock()
+	return growthRatiosTable[growthRatioKey{channelDBID, isShort, basisAgeHours(age), int(horizon.Hours())}]
+}
+
+func growthRatios(trajectories []videoTrajectory, age time.Duration, horizon time.Duration) []float64 {
+	var ratios []float64
+	for _, t := range trajectories {
+		atAge, ok := viewsAtAge(t.series, t.history.PublishedAt, age)
+		if !ok || atAge <= 0 {
+			continue
+		}
+		atHorizon, ok := viewsAtAge(t.series, t.history.PublishedAt, horizon)
+		if !ok {
+			continue
+		}
+		ratios = append(ratios, atHorizon/atAge)
+	}
+	return ratios
+}
+
+// Les prédictions sont conservées par tranche d'âge (grille des courbes de performance)
+// pour mesurer la précision selon l'ancienneté de la vidéo au moment de la prédiction.
+func basisAgeHours(age time.Duration) int {
+	basis := time.Duration(0)
+	for _, a := range curveAges {
+		if a > age {
+			break
+		}
+		basis = a
+	}
+	return int(basis.Hours())
+}
+
+func VideoInfo(dbConn *sql.DB, videoId string) (config.Video, error) {
+	video, err := db.VideoInfo(dbConn, videoId)
+	if err != nil {
+		return video, err
+	}
+	video.Predictions, err = db.LatestVideoPredictions(dbConn, videoId)
+	if err != nil {
+		return video, fmt.Errorf("Erreur lors de la récupération des prédictions : %v", err)
+	}
+	return video, nil
+}
+
+func PredictionAccuracy(dbConn *sql.DB) ([]config.PredictionAccuracy, error) {
+	return db.PredictionAccuracy(dbConn)
+}
@@ -0,0 +1,105 @@
+package logic
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"ytst-back/config"
+	"ytst-back/db"
+	"ytst-back/youtube"
+)
+
+const rssStatsMaxAge = 7 * 24 * time.Hour
+
+// Filet de sécurité du hub : compare le flux RSS de chaque chaîne à la table videos.
+func pollChannelFeeds(dbConn *sql.DB, _ time.Duration) {
+	polls, err := db.ChannelFeedPolls(dbConn)
+	if err != nil {
+		log.Printf("Erreur lors de la récupération des chaînes à interroger : %v", err)
+		return
+	}
+
+	for _, poll := range polls {
+		if err := pollChannelFeed(dbConn, poll); err != nil {
+			log.Printf("Erreur lors de l'interrogation du flux de la chaîne '%s' : %v", poll.ChannelID, err)
+		}
+	}
+}
+
+func pollChannelFeed(dbConn *sql.DB, poll db.ChannelFeedPoll) error {
+	resp, err := youtube.FetchChannelFeed(poll.ChannelID, poll.ETag, poll.LastModified)
+	if resp != nil {
+		if dbErr := db.SaveChannelFeedPoll(dbConn, poll.ChannelDBID, resp.ETag, resp.LastModified, resp.StatusCode); dbErr != nil {
+			log.Printf("Erreur lors de l'enregistrement de l'état du flux pour '%s' : %v", poll.ChannelID, dbErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+
+	var feed config.Feed
+	if err := xml.Unmarshal(resp.Body, &feed); err != nil {
+		return fmt.Errorf("flux illisible : %v", err)
+	}
+
+	var videoIDs []string
+	for _, entry := range feed.Entry {
+		videoIDs = append(videoIDs, entry.VideoId)
+	}
+	known, err := db.AreVideosInBDD(dbConn, videoIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range feed.Entry {
+		if known[entry.VideoId] {
+			continue
+		}
+		// Les vidéos antérieures au suivi de la chaîne n'ont pas été manquées par le hub.
+		published, err := time.Parse(time.RFC3339, entry.Published)
+		if err == nil && published.Before(poll.AddedAt) {
+			continue
+		}
+
+		if err := AddNewVideo(dbConn, entry.VideoId, poll.ChannelID, DiscoveredViaPoll); err != nil {
+			log.Printf("Erreur lors de l'ajout de la vidéo '%s' détectée par RSS : %v", entry.VideoId, err)
+			continue
+		}
+		log.Printf("Vidéo '%s' manquée par le hub, ajoutée via le flux RSS", entry.VideoId)
+	}
+
+	harvestFeedStats(dbConn, feed)
+	return nil
+}
+
+// Les statistiques du flux (media:community) ne coûtent aucun quota : on les relève
+// pour les vidéos récentes à chaque lecture du flux.
+func harvestFeedStats(dbConn *sql.DB, feed config.Feed) {
+	for _, entry := range feed.Entry {
+		community := entry.MediaGroup.Community
+		if community.Statistics.Views == 0 && community.StarRating.Count == 0 {
+			continue
+		}
+
+		videoDBID, err := db.InsertFeedVideoStats(dbConn, entry.VideoId, community.Statistics.Views, community.StarRating.Count, community.StarRating.Average, rssStatsMaxAge)
+		if err != nil {
+			log.Printf("Erreur lors de l'enregistrement des statistiques RSS pour video_id '%s' : %v", entry.VideoId, err)
+			continue
+		}
+		// Chaque nouveau relevé est comparé au précédent, quelle que soit sa source.
+		if videoDBID > 0 {
+			detectVideoMilestones(dbConn, strconv.Itoa(videoDBID))
+		}
+	}
+}
+
+func DiscoveryStats(dbConn *sql.DB) ([]config.DiscoveryStats, error) {
+	return db.DiscoveryStats(dbConn)
+}
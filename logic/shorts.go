@@ -0,0 +1,163 @@
+package logic
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"ytst-back/config"
+	"ytst-back/db"
+	"ytst-back/duration"
+	"ytst-back/youtube"
+)
+
+const (
+	ShortSourceProbe    = "probe"
+	ShortSourceDuration = "duration"
+	ShortSourceUnknown  = "unknown"
+)
+
+var (
+	shortsMaxDuration = 3 * time.Minute
+	shortsProbeURL    = "https://www.youtube.com"
+	shortsProbeClient = &http.Client{
+		Timeout: 10 * time.Second,
+		// On veut lire le code de la première réponse (200 ou 303), pas suivre la redirection.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+)
+
+type ShortClassification struct {
+	IsShort    bool
+	Source     string
+	Confidence float64
+}
+
+func configureShorts(cfg *config.Config) {
+	shortsMaxDuration = cfg.ShortsMaxDuration
+	shortsProbeURL = cfg.ShortsProbeURL
+}
+
+// Récupère la durée ISO 8601 de plusieurs vidéos (50 maximum par appel).
+func fetchVideoDurations(videoIds []string) (map[string]string, error) {
+	data, err := youtube.YouTubeAPIRequest("videos", map[string]string{
+		"part":       "contentDetails",
+		"id":         strings.Join(videoIds, ","),
+		"maxResults": "50",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var details config.YouTubeVideoContentDetails
+	if err := mapToStruct(data, &details); err != nil {
+		return nil, err
+	}
+
+	durations := make(map[string]string, len(details.Items))
+	for _, item := range details.Items {
+		durations[item.ID] = item.ContentDetails.Duration
+	}
+	return durations, nil
+}
+
+// youtube.com/shorts/{id} répond 200 pour un Short et redirige (303) vers /watch sinon.
+// Toute autre réponse (consentement, limitation, ...) est une erreur : la durée prend le relais.
+func probeShort(videoId string) (bool, error) {
+	resp, err := shortsProbeClient.Get(shortsProbeURL + "/shorts/" + videoId)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusSeeOther:
+		if location, err := resp.Location(); err == nil && location.Path == "/watch" {
+			return false, nil
+		}
+		return false, fmt.Errorf("redirection inattendue de la sonde Shorts : %s", resp.Header.Get("Location"))
+	}
+	return false, fmt.Errorf("réponse inattendue de la sonde Shorts : %s", resp.Status)
+}
+
+// La durée tranche seule au-delà du plafond ; en dessous, la sonde /shorts/ décide.
+// Si la sonde échoue, on se rabat sur la durée avec une confiance moindre.
+func classifyShort(videoId string, videoDuration *time.Duration) ShortClassification {
+	knownDuration := videoDuration != nil && *videoDuration > 0
+
+	if knownDuration && *videoDuration > shortsMaxDuration {
+		return ShortClassification{IsShort: false, Source: ShortSourceDuration, Confidence: 1}
+	}
+
+	isShort, err := probeShort(videoId)
+	if err == nil {
+		return ShortClassification{IsShort: isShort, Source: ShortSourceProbe, Confidence: 0.95}
+	}
+	fmt.Printf("Sonde Shorts indisponible pour la vidéo ID %s : %v\n", videoId, err)
+
+	if !knownDuration {
+		return ShortClassification{IsShort: false, Source: ShortSourceUnknown, Confidence: 0}
+	}
+	return ShortClassification{IsShort: true, Source: ShortSourceDuration, Confidence: 0.6}
+}
+
+func parseVideoDuration(videoId string, isoDuration string) *time.Duration {
+	d, err := duration.Parse(isoDuration)
+	if err != nil {
+		fmt.Printf("Durée non reconnue pour la vidéo ID %s : %v\n", videoId, err)
+		return nil
+	}
+	return &d
+}
+
+func durationSeconds(d *time.Duration) *int {
+	if d == nil {
+		return nil
+	}
+	seconds := int(d.Seconds())
+	return &seconds
+}
+
+// Durée (en secondes, nil si inconnue) et classification d'une nouvelle vidéo.
+func classifyVideo(videoId string) (*int, ShortClassification) {
+	durations, err := fetchVideoDurations([]string{videoId})
+	if err != nil {
+		fmt.Printf("Erreur lors de l'appel à l'API YouTube pour video_id '%s': %v\n", videoId, err)
+	}
+
+	videoDuration := parseVideoDuration(videoId, durations[videoId])
+	return durationSeconds(videoDuration), classifyShort(videoId, videoDuration)
+}
+
+// Reclassifie les vidéos existantes et renseigne leur durée ; sans all, seules celles non confirmées par la sonde.
+func ReclassifyShorts(dbConn *sql.DB, all bool) error {
+	videoIDs, err := db.VideoIDsToClassify(dbConn, all)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(videoIDs); start += 50 {
+		batch := videoIDs[start:min(start+50, len(videoIDs))]
+		durations, err := fetchVideoDurations(batch)
+		if err != nil {
+			return fmt.Errorf("erreur lors de la récupération des durées : %v", err)
+		}
+
+		for _, videoID := range batch {
+			videoDuration := parseVideoDuration(videoID, durations[videoID])
+			classification := classifyShort(videoID, videoDuration)
+			if err := db.UpdateVideoClassification(dbConn, videoID, durationSeconds(videoDuration), classification.IsShort, classification.Source, classification.Confidence); err != nil {
+				log.Printf("Erreur lors de la mise à jour de la vidéo '%s' : %v", videoID, err)
+				continue
+			}
+			log.Printf("Vidéo '%s' : short=%t (%s, %.2f)", videoID, classification.IsShort, classification.Source, classification.Confidence)
+		}
+	}
+	return nil
+}
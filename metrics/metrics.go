@@ -0,0 +1,16 @@
+package metrics
+
+import "expvar"
+
+// Compteurs exposés sur /ytbtst/metrics (format expvar).
+var (
+	HubNotifications = expvar.NewMap("hub_notifications")
+)
+
+const (
+	NotificationReceived         = "received"
+	NotificationAccepted         = "accepted"
+	NotificationUnknownTopic     = "unknown_topic"
+	NotificationSignatureMissing = "signature_missing"
+	NotificationSignatureInvalid = "signature_invalid"
+)
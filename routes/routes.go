@@ -1,15 +1,20 @@
 package routes
 
 import (
+	"crypto/subtle"
 	"database/sql"
 	"encoding/xml"
+	"errors"
+	"expvar"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"net/url"
-	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"ytst-back/analytics"
 	"ytst-back/config"
 	"ytst-back/db"
 	"ytst-back/logic"
@@ -18,13 +23,12 @@ import (
 )
 
 var dbConn *sql.DB
-var callbackURL = "https://ytst-back.flgr.fr/youtube/callback"
 
-func SetupRoutes(db *sql.DB) *gin.Engine {
+func SetupRoutes(db *sql.DB, cfg *config.Config) *gin.Engine {
 	router := gin.Default()
 
 	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", config.WebsiteAccess)
+		c.Writer.Header().Set("Access-Control-Allow-Origin", cfg.WebsiteAccess)
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, command, method")
@@ -38,9 +42,10 @@ func SetupRoutes(db *sql.DB) *gin.Engine {
 	})
 	router.GET("/ytbtst/research", ytstResearch)
 	router.POST("/ytbtst/addChannel", keepSubscriptionAlive)
+	router.POST("/ytbtst/unsubscribeChannel", unsubscribeChannel)
 	// router.GET("/ytbtst/checkNewVideos", checkNewVideos)
-	router.GET("/youtube/callback", handleYouTubeHubChallenge)
-	router.POST("/youtube/callback", handleYouTubeNotification)
+	router.GET(config.CallbackPath, handleYouTubeHubChallenge)
+	router.POST(config.CallbackPath, handleYouTubeNotification)
 	router.GET("/ytbtst/channelInfo", channelInfo)
 	router.GET("/ytbtst/channelStats", channelStats)
 	router.GET("/ytbtst/videosFromChannel", videosFromChannel)
@@ -49,10 +54,55 @@ func SetupRoutes(db *sql.DB) *gin.Engine {
 	router.GET("/ytbtst/recuperateLastFollowedChannels", recuperateLastFollowedChannels)
 	router.GET("/ytbtst/recuperateLastFollowedVideos", recuperateLastFollowedVideos)
 
+	admin := router.Group("/ytbtst", adminOnly(cfg))
+	admin.GET("/hubSubscriptions", hubSubscriptions)
+	admin.GET("/metrics", gin.WrapH(expvar.Handler()))
+	admin.GET("/hubNotifications", hubNotifications)
+	admin.POST("/replayHubNotification", replayHubNotification)
+
+	router.GET("/ytbtst/discoveryStats", discoveryStats)
+	router.GET("/ytbtst/channelDurationStats", channelDurationStats)
+	router.GET("/ytbtst/liveStats", liveStats)
+	router.GET("/ytbtst/videoAnalytics", videoAnalytics)
+	router.GET("/ytbtst/channelAnalytics", channelAnalytics)
+	router.GET("/ytbtst/forecast", forecast)
+	router.GET("/ytbtst/outliers", outliers)
+	router.GET("/ytbtst/channelCurve", channelCurve)
+	router.GET("/ytbtst/predictionAccuracy", predictionAccuracy)
+	router.GET("/ytbtst/anomalies", anomalies)
+	router.GET("/ytbtst/videoEngagement", videoEngagement)
+	router.GET("/ytbtst/channelEngagement", channelEngagement)
+	router.GET("/ytbtst/engagementRankings", engagementRankings)
+	router.GET("/ytbtst/uploadCadence", uploadCadence)
+	router.GET("/ytbtst/silentChannels", silentChannels)
+	router.GET("/ytbtst/channelDailySummary", channelDailySummary)
+	router.GET("/ytbtst/compare", compare)
+	router.GET("/ytbtst/channelLeaderboard", channelLeaderboard)
+	router.GET("/ytbtst/videoLeaderboard", videoLeaderboard)
+	router.GET("/ytbtst/milestones", milestones)
+	router.GET("/ytbtst/milestones.atom", milestonesFeed)
+	router.GET("/ytbtst/formatBreakdown", formatBreakdown)
+
 	dbConn = db
 	return router
 }
 
+// Routes d'administration : réservées au site autorisé (WEBSITE_ACCESS) ou aux appels
+// munis du jeton ADMIN_TOKEN (en-tête Authorization: Bearer).
+func adminOnly(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.AdminToken != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+cfg.AdminToken)) == 1 {
+			c.Next()
+			return
+		}
+		if origin := c.GetHeader("Origin"); cfg.WebsiteAccess == "*" || (origin != "" && origin == cfg.WebsiteAccess) {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Accès réservé à l'administration"})
+	}
+}
+
 func ytstResearch(c *gin.Context) {
 	searchValue := c.Query("searchValue")
 	if searchValue == "" {
@@ -71,14 +121,22 @@ func ytstResearch(c *gin.Context) {
 
 func handleYouTubeHubChallenge(c *gin.Context) {
 	mode := c.Query("hub.mode")
+	topic := c.Query("hub.topic")
 	challenge := c.Query("hub.challenge")
 
-	if mode == "subscribe" {
+	switch mode {
+	case "subscribe", "unsubscribe":
+		if !logic.ConfirmHubVerification(dbConn, mode, topic, c.Query("hub.verify_token"), c.Query("hub.lease_seconds")) {
+			c.Status(http.StatusNotFound)
+			return
+		}
 		c.String(http.StatusOK, challenge)
-		return
+	case "denied":
+		logic.RecordHubDenial(dbConn, topic, c.Query("hub.reason"))
+		c.Status(http.StatusOK)
+	default:
+		c.Status(http.StatusBadRequest)
 	}
-
-	c.Status(http.StatusOK)
 }
 
 func handleYouTubeNotification(c *gin.Context) {
@@ -87,52 +145,27 @@ func handleYouTubeNotification(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot read body"})
 		return
 	}
-	var notification config.Feed
-	if err := xml.Unmarshal(body, &notification); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot bind json"})
+
+	topic := logic.NotificationTopic(c.Request.Header.Values("Link"), body)
+	if err := logic.VerifyHubNotification(dbConn, topic, c.GetHeader("X-Hub-Signature"), body); err != nil {
+		// La spécification impose un 2xx même si la signature est invalide : on ignore silencieusement.
+		log.Printf("Notification YouTube ignorée : %v", err)
+		c.Status(http.StatusOK)
 		return
 	}
 
-	if len(notification.Entry) == 0 {
-		fmt.Println("Aucune entrée reçue dans la notification YouTube")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No entries found in notification"})
+	if _, err := logic.ParseHubFeed(body); err != nil {
+		fmt.Printf("Notification YouTube invalide : %v\n", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	err = logic.AddNewVideo(dbConn, notification.Entry[0].VideoId, notification.Entry[0].ChannelId)
-	if err != nil {
+	if err := logic.EnqueueHubNotification(dbConn, topic, body); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.Status(http.StatusOK)
-}
-
-func addChannel(channelId string) error {
-
-	hubURL := "https://pubsubhubbub.appspot.com/subscribe"
-
-	topicURL := "https://www.youtube.com/xml/feeds/videos.xml?channel_id=" + channelId
-
-	form := url.Values{}
-	form.Add("hub.mode", "subscribe")
-	form.Add("hub.topic", topicURL)
-	form.Add("hub.callback", callbackURL)
-	form.Add("hub.lease_seconds", "864000")
-	form.Add("hub.verify", "async")
-	form.Add("hub.verify_token", os.Getenv("YTBToken"))
-
-	resp, err := http.PostForm(hubURL, form)
-	if err != nil {
-		return fmt.Errorf("erreur subscribe: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("status: %d, body: %s", resp.StatusCode, body)
-	}
-	return nil
+	c.Status(http.StatusNoContent)
 }
 
 func keepSubscriptionAlive(c *gin.Context) {
@@ -151,27 +184,29 @@ func keepSubscriptionAlive(c *gin.Context) {
 		return
 	}
 
-	err = addChannel(channelId)
+	err = logic.SubscribeChannel(dbConn, channelId)
 	if err != nil {
 		log.Printf("Erreur initiale d'abonnement: %v", err)
 	}
 
-	ticker := time.NewTicker(864000*time.Second - 24*time.Hour)
-	go func() {
-		for {
-			<-ticker.C
-			err := addChannel(channelId)
-			if err != nil {
-				log.Printf("Erreur de renouvellement d'abonnement: %v", err)
-			} else {
-				log.Printf("Renouvellement d'abonnement OK pour la chaîne: %s", channelId)
-			}
-		}
-	}()
-
 	c.JSON(http.StatusOK, gin.H{"message": "Abonnement en cours"})
 }
 
+func unsubscribeChannel(c *gin.Context) {
+	var req config.AddChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := logic.UnsubscribeChannel(dbConn, req.ChannelID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Désabonnement en cours"})
+}
+
 func channelInfo(c *gin.Context) {
 	channelId := c.Query("channelId")
 	if channelId == "" {
@@ -208,7 +243,18 @@ func videosFromChannel(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'channelId' est requis"})
 	}
 
-	data, err := db.VideosFromChannel(dbConn, channelId)
+	minDuration, err := optionalIntQuery(c, "minDuration")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	maxDuration, err := optionalIntQuery(c, "maxDuration")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := db.VideosFromChannel(dbConn, channelId, minDuration, maxDuration)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -223,7 +269,7 @@ func videoInfo(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'videoId' est requis"})
 	}
 
-	data, err := db.VideoInfo(dbConn, videoId)
+	data, err := logic.VideoInfo(dbConn, videoId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -266,3 +312,609 @@ func recuperateLastFollowedVideos(c *gin.Context) {
 
 	c.JSON(http.StatusOK, data)
 }
+
+func hubSubscriptions(c *gin.Context) {
+	data, err := logic.HubSubscriptions(dbConn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func hubNotifications(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'limit' est invalide"})
+		return
+	}
+
+	data, err := logic.HubNotifications(dbConn, c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func replayHubNotification(c *gin.Context) {
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'id' est requis"})
+		return
+	}
+
+	if err := logic.ProcessHubNotification(dbConn, id, c.Query("force") == "true"); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification rejouée"})
+}
+
+func discoveryStats(c *gin.Context) {
+	data, err := logic.DiscoveryStats(dbConn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func optionalIntQuery(c *gin.Context, name string) (*int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Le paramètre '%s' est invalide", name)
+	}
+	return &value, nil
+}
+
+func channelDurationStats(c *gin.Context) {
+	data, err := logic.ChannelDurationStats(dbConn, c.Query("channelId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func liveStats(c *gin.Context) {
+	videoId := c.Query("videoId")
+	if videoId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'videoId' est requis"})
+		return
+	}
+
+	data, err := logic.LiveStats(dbConn, videoId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func videoAnalytics(c *gin.Context) {
+	videoId := c.Query("videoId")
+	if videoId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'videoId' est requis"})
+		return
+	}
+
+	from, to, bucket, err := analyticsRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := logic.VideoAnalytics(dbConn, videoId, from, to, bucket)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func channelAnalytics(c *gin.Context) {
+	channelId := c.Query("channelId")
+	if channelId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'channelId' est requis"})
+		return
+	}
+
+	from, to, bucket, err := analyticsRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := logic.ChannelAnalytics(dbConn, channelId, from, to, bucket)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// from et to acceptent RFC 3339 ou AAAA-MM-JJ ; bucket accepte les durées Go ainsi que "d" et "w".
+func analyticsRange(c *gin.Context) (*time.Time, *time.Time, time.Duration, error) {
+	from, err := optionalTimeQuery(c, "from")
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	to, err := optionalTimeQuery(c, "to")
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	bucket := 24 * time.Hour
+	if raw := c.Query("bucket"); raw != "" {
+		bucket, err = analytics.ParseWindow(raw)
+		if err != nil || bucket <= 0 {
+			return nil, nil, 0, fmt.Errorf("Le paramètre 'bucket' est invalide")
+		}
+	}
+	return from, to, bucket, nil
+}
+
+func optionalTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if value, err := time.Parse(layout, raw); err == nil {
+			return &value, nil
+		}
+	}
+	return nil, fmt.Errorf("Le paramètre '%s' est invalide", name)
+}
+
+func forecast(c *gin.Context) {
+	channelId, videoId := c.Query("channelId"), c.Query("videoId")
+	if (channelId == "") == (videoId == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Un seul des paramètres 'channelId' ou 'videoId' est requis"})
+		return
+	}
+
+	horizon := 90 * 24 * time.Hour
+	if raw := c.Query("horizon"); raw != "" {
+		var err error
+		horizon, err = analytics.ParseWindow(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'horizon' est invalide"})
+			return
+		}
+	}
+
+	var milestones []float64
+	if raw := c.Query("milestones"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'milestones' est invalide"})
+				return
+			}
+			milestones = append(milestones, value)
+		}
+	}
+
+	var data config.Forecast
+	var err error
+	if channelId != "" {
+		data, err = logic.ChannelForecast(dbConn, channelId, horizon, milestones)
+	} else {
+		data, err = logic.VideoForecast(dbConn, videoId, horizon, milestones)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func outliers(c *gin.Context) {
+	age := 24 * time.Hour
+	if raw := c.Query("age"); raw != "" {
+		parsed, err := analytics.ParseWindow(raw)
+		if err != nil || !slices.Contains(logic.OutlierAges, parsed) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'age' doit valoir 6h, 24h, 7d ou 30d"})
+			return
+		}
+		age = parsed
+	}
+
+	limit, err := positiveIntQuery(c, "limit", 20)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := logic.Outliers(dbConn, age, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func channelCurve(c *gin.Context) {
+	channelId := c.Query("channelId")
+	if channelId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'channelId' est requis"})
+		return
+	}
+
+	data, err := logic.ChannelCurve(dbConn, channelId, c.Query("videoId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func predictionAccuracy(c *gin.Context) {
+	data, err := logic.PredictionAccuracy(dbConn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func anomalies(c *gin.Context) {
+	channelId := c.Query("channelId")
+	if channelId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'channelId' est requis"})
+		return
+	}
+
+	limit, err := positiveIntQuery(c, "limit", 100)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := logic.Anomalies(dbConn, channelId, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func videoEngagement(c *gin.Context) {
+	videoId := c.Query("videoId")
+	if videoId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'videoId' est requis"})
+		return
+	}
+
+	data, err := logic.VideoEngagement(dbConn, videoId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func channelEngagement(c *gin.Context) {
+	channelId := c.Query("channelId")
+	if channelId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'channelId' est requis"})
+		return
+	}
+
+	last, err := positiveIntQuery(c, "last", 20)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := logic.ChannelEngagement(dbConn, channelId, last)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func engagementRankings(c *gin.Context) {
+	format := c.DefaultQuery("format", logic.FormatAll)
+	if format != logic.FormatAll && format != logic.FormatShort && format != logic.FormatLong {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'format' doit valoir all, short ou long"})
+		return
+	}
+
+	last, err := positiveIntQuery(c, "last", 20)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := logic.EngagementRankings(dbConn, format, last)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func positiveIntQuery(c *gin.Context, name string, fallback int) (int, error) {
+	value, err := optionalIntQuery(c, name)
+	if err != nil {
+		return 0, err
+	}
+	if value == nil {
+		return fallback, nil
+	}
+	if *value <= 0 {
+		return 0, fmt.Errorf("Le paramètre '%s' est invalide", name)
+	}
+	return *value, nil
+}
+
+func uploadCadence(c *gin.Context) {
+	channelId := c.Query("channelId")
+	if channelId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'channelId' est requis"})
+		return
+	}
+
+	location, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'tz' est invalide"})
+		return
+	}
+
+	data, err := logic.UploadCadence(dbConn, channelId, location, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func silentChannels(c *gin.Context) {
+	data, err := logic.SilentChannels(dbConn, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func channelDailySummary(c *gin.Context) {
+	channelId := c.Query("channelId")
+	if channelId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'channelId' est requis"})
+		return
+	}
+
+	// days=0 : tout l'historique
+	days := 30
+	value, err := optionalIntQuery(c, "days")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if value != nil {
+		if *value < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'days' est invalide"})
+			return
+		}
+		days = *value
+	}
+
+	data, err := logic.ChannelDailySummary(dbConn, channelId, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// channelIds ou videoIds : liste d'identifiants séparés par des virgules.
+func compare(c *gin.Context) {
+	channelIds, videoIds := splitQuery(c, "channelIds"), splitQuery(c, "videoIds")
+	if (len(channelIds) == 0) == (len(videoIds) == 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Un seul des paramètres 'channelIds' ou 'videoIds' est requis"})
+		return
+	}
+	if len(channelIds) > logic.MaxComparedItems || len(videoIds) > logic.MaxComparedItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Au plus %d éléments peuvent être comparés", logic.MaxComparedItems)})
+		return
+	}
+
+	from, to, bucket, err := analyticsRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var data config.Comparison
+	if len(channelIds) > 0 {
+		data, err = logic.CompareChannels(dbConn, channelIds, from, to, bucket)
+	} else {
+		if c.Query("bucket") == "" {
+			bucket = 6 * time.Hour
+		}
+		data, err = logic.CompareVideos(dbConn, videoIds, bucket)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func splitQuery(c *gin.Context, name string) []string {
+	var values []string
+	for _, part := range strings.Split(c.Query(name), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+func channelLeaderboard(c *gin.Context) {
+	window, filters, page, pageSize, err := leaderboardParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Le format et la catégorie sont propres aux vidéos
+	if filters.Format != "" || filters.Category != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Les paramètres 'format' et 'category' ne s'appliquent qu'au classement des vidéos"})
+		return
+	}
+
+	data, err := logic.ChannelLeaderboard(dbConn, c.DefaultQuery("metric", "subscribers"), window, filters, page, pageSize)
+	if errors.Is(err, logic.ErrUnknownLeaderboardMetric) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func videoLeaderboard(c *gin.Context) {
+	window, filters, page, pageSize, err := leaderboardParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := logic.VideoLeaderboard(dbConn, c.DefaultQuery("metric", "views_gain"), window, filters, page, pageSize)
+	if errors.Is(err, logic.ErrUnknownLeaderboardMetric) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func leaderboardParams(c *gin.Context) (time.Duration, config.LeaderboardFilters, int, int, error) {
+	filters := config.LeaderboardFilters{
+		Country:  c.Query("country"),
+		Format:   c.Query("format"),
+		Category: c.Query("category"),
+	}
+	if filters.Format != "" && filters.Format != logic.FormatShort && filters.Format != logic.FormatLong {
+		return 0, filters, 0, 0, fmt.Errorf("Le paramètre 'format' doit valoir short ou long")
+	}
+
+	window := 7 * 24 * time.Hour
+	if raw := c.Query("window"); raw != "" {
+		var err error
+		if window, err = analytics.ParseWindow(raw); err != nil {
+			return 0, filters, 0, 0, fmt.Errorf("Le paramètre 'window' est invalide")
+		}
+	}
+
+	page, err := positiveIntQuery(c, "page", 1)
+	if err != nil {
+		return 0, filters, 0, 0, err
+	}
+	pageSize, err := positiveIntQuery(c, "pageSize", 25)
+	if err != nil {
+		return 0, filters, 0, 0, err
+	}
+	if pageSize > logic.MaxLeaderboardPageSize {
+		pageSize = logic.MaxLeaderboardPageSize
+	}
+	return window, filters, page, pageSize, nil
+}
+
+func milestones(c *gin.Context) {
+	limit, err := positiveIntQuery(c, "limit", 100)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := logic.MilestoneEvents(dbConn, c.Query("channelId"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func milestonesFeed(c *gin.Context) {
+	limit, err := positiveIntQuery(c, "limit", 50)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	feed, err := logic.MilestoneFeed(dbConn, c.Query("channelId"), limit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
+
+func formatBreakdown(c *gin.Context) {
+	channelId := c.Query("channelId")
+	if channelId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'channelId' est requis"})
+		return
+	}
+
+	months, err := positiveIntQuery(c, "months", 12)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if months > logic.MaxBreakdownMonths {
+		months = logic.MaxBreakdownMonths
+	}
+
+	data, err := logic.ChannelFormatBreakdown(dbConn, channelId, months, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -8,19 +8,13 @@ import (
 	"ytst-back/db"
 	"ytst-back/logic"
 	"ytst-back/routes"
+	"ytst-back/youtube"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
 var dbConn *sql.DB
 
-func init() {
-	if err := godotenv.Load(); err != nil {
-		panic("Erreur lors du chargement du fichier .env")
-	}
-}
-
 func main() {
 
 	cfg, err := config.Load()
@@ -38,9 +32,12 @@ func main() {
 		log.Fatalf("Erreur lors de la création des tables : %v", err)
 	}
 
-	router := routes.SetupRoutes(dbConn)
+	youtube.Configure(cfg)
+	logic.Configure(cfg)
+	router := routes.SetupRoutes(dbConn, cfg)
 	// router.GET("/ytbtst/refreshChannelStats", refreshChannelStats)
-	logic.PeriodicallyCalledRoutes(dbConn)
+	logic.StartNotificationWorker(dbConn)
+	logic.PeriodicallyCalledRoutes(dbConn, cfg)
 
 	router.Run(":4000")
 
@@ -0,0 +1,56 @@
+package youtube
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type FeedResponse struct {
+	StatusCode   int
+	Body         []byte
+	ETag         string
+	LastModified string
+}
+
+// Récupère le flux Atom public d'une chaîne (sans quota), en GET conditionnel.
+func FetchChannelFeed(channelID string, etag string, lastModified string) (*FeedResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, feedURL+"?channel_id="+channelID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de l'envoi de la requête : %v", err)
+	}
+	defer resp.Body.Close()
+
+	feed := &FeedResponse{
+		StatusCode:   resp.StatusCode,
+		ETag:         etag,
+		LastModified: lastModified,
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return feed, nil
+	case http.StatusOK:
+	default:
+		return feed, fmt.Errorf("erreur flux YouTube : %s", resp.Status)
+	}
+
+	feed.Body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return feed, fmt.Errorf("erreur lors de la lecture du flux : %v", err)
+	}
+	feed.ETag = resp.Header.Get("ETag")
+	feed.LastModified = resp.Header.Get("Last-Modified")
+	return feed, nil
+}
@@ -4,16 +4,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
+	"ytst-back/config"
 )
 
+var (
+	apiKey  string
+	apiURL  = "https://www.googleapis.com/youtube/v3"
+	feedURL = "https://www.youtube.com/feeds/videos.xml"
+)
+
+func Configure(cfg *config.Config) {
+	apiKey = cfg.YouTubeAPIKey
+	apiURL = cfg.YouTubeAPIURL
+	feedURL = cfg.YouTubeFeedURL
+}
+
 func YouTubeAPIRequest(endpoint string, queryParams map[string]string) (map[string]interface{}, error) {
-	youtubeApiKey := os.Getenv("GOOGLE_API_KEY")
-	if youtubeApiKey == "" {
+	if apiKey == "" {
 		return nil, fmt.Errorf("clé API Google manquante")
 	}
 
-	url := fmt.Sprintf("https://www.googleapis.com/youtube/v3/%s?key=%s", endpoint, youtubeApiKey)
+	url := fmt.Sprintf("%s/%s?key=%s", apiURL, endpoint, apiKey)
 	for key, value := range queryParams {
 		url += fmt.Sprintf("&%s=%s", key, value)
 	}